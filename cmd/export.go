@@ -0,0 +1,53 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/peschmae/glab-component-generator/pkg/gitlab"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export",
+		Aliases: []string{"e"},
+		Short:   "Exports a machine-readable description of all components within the given project directory",
+		Long: `Gathers all components in <project>/templates and writes their name, path,
+inputs and jobs as JSON or YAML.
+
+By default the description is written to stdout.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportComponents(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "-", "The path to the output file, - for stdout. Relative to the current directory")
+	cmd.Flags().StringP("format", "f", "json", "The output format, either json or yaml")
+
+	addComponentFileFlags(cmd)
+
+	return cmd
+}
+
+func exportComponents(out io.Writer) error {
+	components, err := loadComponents()
+	if err != nil {
+		return err
+	}
+
+	b, err := gitlab.Marshal(gitlab.Export(viper.GetString("project"), components), viper.GetString("format"))
+	if err != nil {
+		return err
+	}
+
+	if viper.GetString("output") == "-" {
+		_, err = out.Write(b)
+		return err
+	}
+	return os.WriteFile(viper.GetString("output"), b, 0644)
+}
@@ -5,10 +5,11 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
 
+	"github.com/peschmae/glab-component-generator/pkg/diff"
 	"github.com/peschmae/glab-component-generator/pkg/gitlab"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,104 +24,156 @@ func NewGenerateCommand() *cobra.Command {
 from them using the inputs spec.
 
 The generated README is prepended by a HEADER and FOOTER file, if present.
-The same goes for each component.`,
-		PreRunE: func(cmd *cobra.Command, args []string) error {
-			return validateFlags()
-		},
+The same goes for each component. Single file components use a markdown file
+next to them as header, eg. templates/build.md for templates/build.yml. With
+--header-from-comment, the comment block at the top of the template is used for
+components without header file.
+
+With --check the README is only rendered in memory and compared to the existing
+output file. If they differ, a diff is printed and the command fails without
+writing anything.
+
+With --inject the existing output file is kept and only the content between
+markers is replaced. Supported markers are
+  <!-- BEGIN COMPONENTS --> / <!-- END COMPONENTS -->
+    all components
+  <!-- BEGIN COMPONENT <name> --> / <!-- END COMPONENT <name> -->
+    a single component
+  <!-- BEGIN TOC --> / <!-- END TOC -->
+    a table of contents linking all components
+  <!-- BEGIN SUMMARY --> / <!-- END SUMMARY -->
+    a table summarizing all components
+The header and footer files are not used in this mode.
+
+Each component is rendered using a Go template, which gets the whole component
+passed. The built-in template can be replaced using --template, and a single
+component can override it with a template file in its directory.
+
+With --component-readme a README is written into the directory of each directory
+component, and the output file only links to them. Components consisting of a
+single file are still rendered into the output file.
+
+The components are ordered by their path, or by their name using --sort name.
+If a component has a category, set in the front matter of its HEADER.md or in
+the config file, the components are grouped below a heading per category.
+
+With --toc the default header contains a generated table of contents instead of
+the [[_TOC_]] macro, which is only rendered by GitLab. A custom header can place
+it using the placeholder {{toc}}.
+
+With --summary a table listing each component with its description and the
+number of its mandatory and optional inputs is rendered before the components.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return generateReadme()
+			return generateReadme(cmd.OutOrStdout())
 		},
 	}
 
-	cmd.Flags().StringP("project", "p", ".", "The path to the gitlab CI component project")
 	cmd.Flags().StringP("output", "o", "README.md", "The path to the output file. Relative to the projet directory")
 
 	cmd.Flags().String("header", "HEADER.md", "File to prepended to the list of components")
 	cmd.Flags().String("footer", "FOOTER.md", "File to appended to the list of components")
 
-	cmd.Flags().String("component-header", "HEADER.md", "File to prepended on component. The file must exist in the component directory")
-	cmd.Flags().String("component-footer", "FOOTER.md", "File to appended on component. The file must exist in the component directory")
+	addComponentFileFlags(cmd)
 
+	cmd.Flags().Bool("header-from-comment", false, "Use the comment block at the top of a template as header, if the component has no header file")
 	cmd.Flags().Int("component-header-level", 2, "The level of the header for each component")
+	cmd.Flags().String("sort", gitlab.SortByPath, "The order of the components, either path or name. Components listed in order in the config file come first")
+	cmd.Flags().Bool("toc", false, "Generate the table of contents in the default header instead of using the [[_TOC_]] macro of GitLab")
+	cmd.Flags().Bool("summary", false, "Render a table summarizing all components before the components")
 
-	// bind flags to viper
-	viper.BindPFlag("project", cmd.Flags().Lookup("project"))
-	viper.BindPFlag("output", cmd.Flags().Lookup("output"))
+	cmd.Flags().String("template", "", "Go template file used to render each component. Relative to the project directory")
+	cmd.Flags().String("component-template", "README.md.tmpl", "Go template file overriding the template of a component. The file must exist in the component directory")
 
-	viper.BindPFlag("header", cmd.Flags().Lookup("header"))
-	viper.BindPFlag("footer", cmd.Flags().Lookup("footer"))
+	cmd.Flags().Bool("usage", false, "Render an include snippet for each component")
+	cmd.Flags().String("usage-project", "", "The path of the component project used in the include snippet, eg. my-group/my-components")
+	cmd.Flags().String("usage-version", "~latest", "The version of the components used in the include snippet")
+	cmd.Flags().Bool("usage-optional", false, "Add the optional inputs with their default to the include snippet")
 
-	viper.BindPFlag("component-header", cmd.Flags().Lookup("component-header"))
-	viper.BindPFlag("component-footer", cmd.Flags().Lookup("component-footer"))
+	cmd.Flags().Bool("component-readme", false, "Write a README into each component directory and only link them from the output file")
+	cmd.Flags().String("component-readme-file", "README.md", "The name of the README written into each component directory")
 
-	viper.BindPFlag("component-header-level", cmd.Flags().Lookup("component-header-level"))
+	cmd.Flags().Bool("check", false, "Do not write the output files, but fail if they are not up to date")
+	cmd.Flags().Bool("inject", false, "Only replace the content between markers in the existing output file")
 
 	return cmd
 }
 
-func generateReadme() error {
-	components := []string{}
-	templatePath := filepath.Join(viper.GetString("project"), "templates")
-	// find all yaml files in project
-	filepath.Walk(templatePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		// within the templates directory, we take all the yaml/yml files
-		if filepath.Dir(path) == templatePath && (filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml") {
-			components = append(components, path)
-		} else if filepath.Dir(path) != templatePath && (filepath.Base(path) == "template.yaml" || filepath.Base(path) == "template.yml") {
-			// if we are in a subdirectory, only the template.yaml/yml files are relevant
-			components = append(components, path)
-		}
-		return nil
-	})
+func generateReadme(out io.Writer) error {
+	files, err := renderReadme()
+	if err != nil {
+		return err
+	}
 
-	var sb strings.Builder
-	if _, err := os.Stat(filepath.Join(viper.GetString("project"), viper.GetString("header"))); err == nil {
-		header, err := os.ReadFile(filepath.Join(viper.GetString("project"), viper.GetString("header")))
-		if err != nil {
-			return err
-		}
-		sb.WriteString(string(header))
-	} else {
-		sb.WriteString("# GitLab CI Components\n\nThis repository contains the following components:\n\n[[_TOC_]]\n")
+	if viper.GetBool("check") {
+		return checkFiles(out, files)
 	}
 
-	sb.WriteString("\n")
-	// for each yaml file, parse and render the markdown
-	for i := 0; i < len(components); i++ {
-		c, err := gitlab.NewComponent(components[i])
-		if err != nil {
+	// write to file
+	for _, f := range files {
+		if err := os.WriteFile(f.Path, []byte(f.Content), 0644); err != nil {
 			return err
 		}
-		// render markdown
-		sb.WriteString(c.Markdown())
 	}
+	return nil
+}
 
-	if _, err := os.Stat(filepath.Join(viper.GetString("project"), viper.GetString("footer"))); err == nil {
-		footer, err := os.ReadFile(filepath.Join(viper.GetString("project"), viper.GetString("footer")))
-		if err != nil {
+// checkFiles compares the rendered files with the existing ones and prints a
+// diff for each file which doesn't match
+func checkFiles(out io.Writer, files []gitlab.File) error {
+	outdated := []string{}
+	for _, f := range files {
+		existing, err := os.ReadFile(f.Path)
+		if err != nil && !os.IsNotExist(err) {
 			return err
 		}
-		sb.WriteString(string(footer))
-	}
 
-	sb.WriteString("\n")
+		d := diff.Unified(f.Path, f.Path+" (generated)", string(existing), f.Content)
+		if d == "" {
+			continue
+		}
 
-	// write to file
-	err := os.WriteFile(filepath.Join(viper.GetString("project"), viper.GetString("output")), []byte(strings.TrimSpace(sb.String())+"\n"), 0644)
-	if err != nil {
-		return err
+		fmt.Fprint(out, d)
+		outdated = append(outdated, f.Path)
+	}
+
+	if len(outdated) > 0 {
+		return fmt.Errorf("%s not up to date, run the readme command without --check to update", strings.Join(outdated, ", "))
 	}
 	return nil
 }
 
-func validateFlags() error {
-	// Check if project exists
-	if _, err := os.Stat(viper.GetString("project")); os.IsNotExist(err) {
-		return fmt.Errorf("project does not exist")
+func renderReadme() ([]gitlab.File, error) {
+	project, err := openProject()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	opts, err := options()
+	if err != nil {
+		return nil, err
+	}
+
+	readmeOpts := gitlab.ReadmeOptions{
+		Options:   opts,
+		Output:    projectPath(viper.GetString("output")),
+		Header:    viper.GetString("header"),
+		Footer:    viper.GetString("footer"),
+		TOC:       viper.GetBool("toc"),
+		Summary:   viper.GetBool("summary"),
+		Inject:    viper.GetBool("inject"),
+		KeepGoing: viper.GetBool("keep-going"),
+	}
+	if viper.GetBool("component-readme") {
+		readmeOpts.ComponentReadme = viper.GetString("component-readme-file")
+	}
+	if readmeOpts.Inject {
+		// only the content between the markers in the existing output file is replaced
+		existing, err := os.ReadFile(readmeOpts.Output)
+		if err != nil {
+			return nil, err
+		}
+		readmeOpts.Existing = string(existing)
+	}
+
+	return project.Readme(readmeOpts)
 }
@@ -0,0 +1,137 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/peschmae/glab-component-generator/pkg/archive"
+	"github.com/peschmae/glab-component-generator/pkg/gitlab"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "lint",
+		Aliases: []string{"l"},
+		Short:   "Validates the spec of all components within the given project directory",
+		Long: `Gathers all components in <project>/templates and validates their inputs spec.
+
+The following is reported
+  - unknown keys on an input
+  - keys with a value of the wrong kind, eg. options which are not a list
+  - unknown types
+  - regexes which don't compile
+  - defaults which are not one of the options
+  - defaults which don't match the regex
+  - defaults which don't match the type
+  - inputs used in the jobs with $[[ inputs.name ]], which are not declared (error)
+  - inputs which are declared, but never used in the jobs (warning)
+
+The command fails if at least one error was found.
+
+With --report-format codequality the findings are written as GitLab Code Quality
+report, which shows them inline in merge requests. With --report-format sarif
+they are written as SARIF 2.1.0.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return lintComponents(cmd.OutOrStdout())
+		},
+	}
+
+	addReportFlags(cmd)
+
+	return cmd
+}
+
+func lintComponents(out io.Writer) error {
+	project, err := openProject()
+	if err != nil {
+		return err
+	}
+	components, err := project.FindComponents()
+	if err != nil {
+		return err
+	}
+
+	findings := []gitlab.Finding{}
+	errs := []error{}
+	for _, path := range components {
+		f, err := project.Lint(path)
+		if err != nil {
+			if !viper.GetBool("keep-going") {
+				return err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		findings = append(findings, f...)
+	}
+
+	// GitLab matches the paths with the repository, which is usually the current
+	// directory. An archive has no place in it, so its paths are relative to it.
+	root := "."
+	if archive.IsArchive(viper.GetString("project")) {
+		root = viper.GetString("project")
+	}
+	errs = append(errs, reportFindings(out, findings, fmt.Sprintf("%d component(s)", len(components)), root))
+	return errors.Join(errs...)
+}
+
+// reportFindings writes the findings in the report format and fails if at least
+// one of them is an error. The paths of the reports are relative to root.
+func reportFindings(out io.Writer, findings []gitlab.Finding, checked, root string) error {
+	b, err := gitlab.MarshalFindings(findings, viper.GetString("report-format"), root)
+	if err != nil {
+		return err
+	}
+	if viper.GetString("report-file") == "-" {
+		if _, err := out.Write(b); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(viper.GetString("report-file"), b, 0644); err != nil {
+		return err
+	}
+
+	if gitlab.HasErrors(findings) {
+		return fmt.Errorf("found %d problem(s) in %s", len(findings), checked)
+	}
+	return nil
+}
+
+// addComponentFileFlags adds the flags of the files read from the directory of
+// a component
+func addComponentFileFlags(cmd *cobra.Command) {
+	cmd.Flags().String("component-header", "HEADER.md", "File to prepended on component. The file must exist in the component directory")
+	cmd.Flags().String("component-footer", "FOOTER.md", "File to appended on component. The file must exist in the component directory")
+}
+
+// addReportFlags adds the flags of the commands reporting findings
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().String("report-format", "text", "The format of the findings, either text, codequality or sarif")
+	cmd.Flags().String("report-file", "-", "The path to the file the findings are written to, - for stdout. Relative to the current directory")
+}
+
+// loadComponents loads all components within the project
+func loadComponents() ([]*gitlab.Component, error) {
+	project, err := openProject()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := options()
+	if err != nil {
+		return nil, err
+	}
+	return loadProjectComponents(project, opts)
+}
+
+// loadProjectComponents loads all components within the project and sorts them.
+// With --keep-going, the components which could be loaded are returned together
+// with the errors of all others.
+func loadProjectComponents(project *gitlab.Project, opts gitlab.Options) ([]*gitlab.Component, error) {
+	return project.Components(opts, viper.GetBool("keep-going"))
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/peschmae/glab-component-generator/pkg/archive"
+	"github.com/peschmae/glab-component-generator/pkg/gitlab"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewNewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "new <name>",
+		Aliases: []string{"init"},
+		Short:   "Creates a new component within the given project directory",
+		Long: `Creates the component <name> in <project>/templates.
+
+By default the component gets its own directory, containing the template.yml
+with a spec and a job, as well as empty component header and footer files.
+With --single-file only templates/<name>.yml is created.
+
+Inputs can be added to the spec using --from-inputs with a comma separated list
+of name[:type[:default]], eg. --from-inputs stage::test,concurrency:number:1.
+Commas within brackets are part of the default, eg. paths:array:[a, b].
+
+Empty --component-header or --component-footer skip the file.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			return gitlab.ValidateComponentName(args[0])
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return newComponent(cmd.OutOrStdout(), args[0])
+		},
+	}
+
+	cmd.Flags().Bool("single-file", false, "Create the component as a single file, without its own directory")
+	cmd.Flags().String("from-inputs", "", "Comma separated list of inputs as name[:type[:default]]")
+
+	addComponentFileFlags(cmd)
+
+	return cmd
+}
+
+func newComponent(out io.Writer, name string) error {
+	if archive.IsArchive(viper.GetString("project")) {
+		return fmt.Errorf("new can't write into an archive")
+	}
+
+	inputs, err := gitlab.ParseInputDefinitions(viper.GetString("from-inputs"))
+	if err != nil {
+		return err
+	}
+
+	templates := filepath.Join(viper.GetString("project"), "templates")
+	files := []gitlab.File{}
+	if viper.GetBool("single-file") {
+		files = append(files, gitlab.File{Path: filepath.Join(templates, name+".yml"), Content: gitlab.ScaffoldTemplate(name, inputs)})
+	} else {
+		dir := filepath.Join(templates, name)
+		files = append(files, gitlab.File{Path: filepath.Join(dir, "template.yml"), Content: gitlab.ScaffoldTemplate(name, inputs)})
+		for _, key := range []string{"component-header", "component-footer"} {
+			if file := viper.GetString(key); file != "" {
+				files = append(files, gitlab.File{Path: filepath.Join(dir, file)})
+			}
+		}
+	}
+
+	// never overwrite an existing component
+	existing := []string{filepath.Join(templates, name+".yml"), filepath.Join(templates, name+".yaml"), filepath.Join(templates, name)}
+	for _, path := range existing {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("component %s already exists at %s", name, path)
+		}
+	}
+
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.Path, []byte(f.Content), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "created %s\n", f.Path)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/peschmae/glab-component-generator/pkg/gitlab"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+func NewRenderCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render <component>",
+		Short: "Renders the jobs of a component with the given inputs",
+		Long: `Reads the template of the given component in <project>/templates, and replaces
+all $[[ inputs.name ]] interpolations with the given inputs or their default.
+The interpolation functions expand_vars, truncate and posix_escape are supported.
+
+Inputs are given using --input name=value, or in a YAML file containing a mapping
+of inputs using --inputs-file. Values given using --input are converted to the
+type of the input. The variables expanded by expand_vars are given using
+--variable NAME=value.
+
+The resulting CI configuration is printed to stdout.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return renderComponent(cmd.OutOrStdout(), args[0])
+		},
+	}
+
+	cmd.Flags().StringArrayP("input", "i", []string{}, "Input passed to the component as name=value, can be repeated")
+	cmd.Flags().String("inputs-file", "", "YAML file with a mapping of inputs passed to the component")
+	cmd.Flags().StringArray("variable", []string{}, "Variable used by expand_vars as NAME=value, can be repeated")
+
+	return cmd
+}
+
+func renderComponent(out io.Writer, name string) error {
+	project, err := openProject()
+	if err != nil {
+		return err
+	}
+	opts, err := options()
+	if err != nil {
+		return err
+	}
+	// only the given component is loaded, other broken components don't matter
+	template, err := project.FindComponent(name)
+	if err != nil {
+		return err
+	}
+	c, err := project.Component(template, opts)
+	if err != nil {
+		return err
+	}
+	spec := c.Spec
+	if spec == nil {
+		spec = &gitlab.ComponentSpec{}
+	}
+
+	inputs := map[string]any{}
+	if viper.GetString("inputs-file") != "" {
+		b, err := os.ReadFile(viper.GetString("inputs-file"))
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(b, &inputs); err != nil {
+			return fmt.Errorf("%s: %w", viper.GetString("inputs-file"), err)
+		}
+	}
+
+	for _, input := range viper.GetStringSlice("input") {
+		key, raw, ok := strings.Cut(input, "=")
+		if !ok {
+			return fmt.Errorf("input %q must be given as name=value", input)
+		}
+		if inputs[key], err = spec.ParseInputValue(key, raw); err != nil {
+			return err
+		}
+	}
+
+	variables := map[string]string{}
+	for _, variable := range viper.GetStringSlice("variable") {
+		key, value, ok := strings.Cut(variable, "=")
+		if !ok {
+			return fmt.Errorf("variable %q must be given as NAME=value", variable)
+		}
+		variables[key] = value
+	}
+
+	// the template is read through the project, which may be an archive
+	b, err := project.ReadFile(template)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.Path, err)
+	}
+
+	rendered, err := gitlab.Interpolate(b, spec, inputs, variables)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.Path, err)
+	}
+
+	_, err = fmt.Fprint(out, rendered)
+	return err
+}
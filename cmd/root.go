@@ -4,15 +4,49 @@ Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
 package cmd
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/peschmae/glab-component-generator/pkg/archive"
+	"github.com/peschmae/glab-component-generator/pkg/gitlab"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// configFile is looked up in the project directory, if no config file is given
+const configFile = ".glab-component-generator.yaml"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "glab-component-generator",
 	Short: "Small CLI with commands intended to help handling GitLab CI components",
+	Long: `Small CLI with commands intended to help handling GitLab CI components.
+
+All flags can also be set in a config file, by default ` + configFile + `
+in the project directory, or using environment variables prefixed with
+GLAB_COMPONENT_GENERATOR_, eg. GLAB_COMPONENT_GENERATOR_COMPONENT_HEADER_LEVEL=3.
+
+A flag takes precedence over an environment variable, which takes precedence
+over the config file.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		bindFlags(cmd)
+		if err := loadConfig(); err != nil {
+			// the flags are fine, the config file is broken
+			cmd.SilenceUsage = true
+			return err
+		}
+		if err := validateFlags(); err != nil {
+			return err
+		}
+		// errors past this point are not caused by wrong usage
+		cmd.SilenceUsage = true
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -24,6 +58,141 @@ func Execute() {
 	}
 }
 
+// bindFlags binds the flags of the command to viper. This has to happen when the
+// command is run, as multiple commands share the same keys.
+func bindFlags(cmd *cobra.Command) {
+	viper.BindPFlags(cmd.Flags())
+}
+
+// validateFlags checks the flags shared by multiple commands
+func validateFlags() error {
+	// Check if project exists
+	if _, err := os.Stat(viper.GetString("project")); os.IsNotExist(err) {
+		return fmt.Errorf("project does not exist")
+	}
+
+	if viper.GetBool("component-readme") && archive.IsArchive(viper.GetString("project")) {
+		return fmt.Errorf("--component-readme can't write into an archive")
+	}
+
+	if viper.GetBool("usage") && viper.GetString("usage-project") == "" {
+		return fmt.Errorf("--usage-project is required to render include snippets")
+	}
+
+	return nil
+}
+
+// loadConfig reads the config file given by --config, or the default config
+// file within the project directory if it exists
+func loadConfig() error {
+	viper.SetEnvPrefix("GLAB_COMPONENT_GENERATOR")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
+
+	path := viper.GetString("config")
+	if path == "" && archive.IsArchive(viper.GetString("project")) {
+		// the config file is part of the archive, if it was released with one
+		project, err := openProject()
+		if err != nil {
+			return err
+		}
+		b, err := project.ReadFile(configFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Join(project.Root, configFile), err)
+		}
+		viper.SetConfigType("yaml")
+		return configError(filepath.Join(project.Root, configFile), viper.ReadConfig(bytes.NewReader(b)))
+	}
+	if path == "" {
+		path = filepath.Join(viper.GetString("project"), configFile)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+	}
+
+	viper.SetConfigFile(path)
+	return configError(path, viper.ReadInConfig())
+}
+
+// configError adds the path of the config file to errors parsing it
+func configError(path string, err error) error {
+	var parseErr viper.ConfigParseError
+	if errors.As(err, &parseErr) {
+		return gitlab.NewFileError(path, parseErr.Unwrap())
+	}
+	return err
+}
+
+// openedArchive is the archive opened by openProject, so it is only read once
+// for the config file and the components
+var openedArchive *gitlab.Project
+
+// openProject opens the project given by --project, which is either a directory
+// or a .zip or .tar.gz archive
+func openProject() (*gitlab.Project, error) {
+	project := viper.GetString("project")
+	if !archive.IsArchive(project) {
+		return gitlab.NewProject(project), nil
+	}
+	if openedArchive != nil && openedArchive.Root == project {
+		return openedArchive, nil
+	}
+
+	fsys, err := archive.Open(project)
+	if err != nil {
+		return nil, err
+	}
+	openedArchive = &gitlab.Project{FS: fsys, Root: project}
+	return openedArchive, nil
+}
+
+// projectPath returns the path of a file written into the project. Archives are
+// read-only, so the file is written relative to the current directory instead.
+func projectPath(name string) string {
+	if archive.IsArchive(viper.GetString("project")) {
+		return name
+	}
+	return filepath.Join(viper.GetString("project"), name)
+}
+
+// options maps the flags and the config file to the options of the components
+func options() (gitlab.Options, error) {
+	opts := gitlab.Options{
+		ComponentHeader:   viper.GetString("component-header"),
+		ComponentFooter:   viper.GetString("component-footer"),
+		ComponentTemplate: viper.GetString("component-template"),
+		Template:          viper.GetString("template"),
+		HeaderLevel:       viper.GetInt("component-header-level"),
+		HeaderFromComment: viper.GetBool("header-from-comment"),
+		Sort:              viper.GetString("sort"),
+		Order:             viper.GetStringSlice("order"),
+	}
+	if viper.GetBool("usage") {
+		opts.Usage = &gitlab.UsageOptions{
+			Project:  viper.GetString("usage-project"),
+			Version:  viper.GetString("usage-version"),
+			Optional: viper.GetBool("usage-optional"),
+		}
+	}
+	if err := viper.UnmarshalKey("components", &opts.Components); err != nil {
+		return opts, fmt.Errorf("components: %w", err)
+	}
+	return opts, nil
+}
+
 func init() {
+	rootCmd.PersistentFlags().StringP("config", "c", "", "Config file, defaults to "+configFile+" in the project directory")
+	rootCmd.PersistentFlags().StringP("project", "p", ".", "The path to the gitlab CI component project, either a directory or a .zip or .tar.gz archive")
+	rootCmd.PersistentFlags().Bool("keep-going", false, "Report the errors of all components together instead of stopping at the first one")
+
 	rootCmd.AddCommand(NewGenerateCommand())
+	rootCmd.AddCommand(NewLintCommand())
+	rootCmd.AddCommand(NewExportCommand())
+	rootCmd.AddCommand(NewSchemaCommand())
+	rootCmd.AddCommand(NewValidateUsageCommand())
+	rootCmd.AddCommand(NewRenderCommand())
+	rootCmd.AddCommand(NewNewCommand())
 }
@@ -0,0 +1,100 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/peschmae/glab-component-generator/pkg/gitlab"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewSchemaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schema [component]",
+		Aliases: []string{"s"},
+		Short:   "Generates a JSON Schema for the inputs of each component within the given project directory",
+		Long: `Gathers all components in <project>/templates and generates a JSON Schema
+from the inputs spec of each of them.
+
+The schemas are written to <output-dir>/<component>.schema.json. If a component
+is given, only its schema is written to stdout.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generateSchemas(cmd.OutOrStdout(), args)
+		},
+	}
+
+	cmd.Flags().String("output-dir", "schemas", "The directory the schemas are written to. Relative to the project directory")
+
+	return cmd
+}
+
+func generateSchemas(out io.Writer, args []string) error {
+	if len(args) > 0 {
+		return writeSchema(out, args[0])
+	}
+
+	components, err := loadComponents()
+	if err != nil {
+		return err
+	}
+
+	outputDir := projectPath(viper.GetString("output-dir"))
+	for _, c := range components {
+		b, err := marshalSchema(c)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, c.Name+".schema.json"), b, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSchema writes the schema of a single component to out. Only the given
+// component is loaded, other broken components don't matter.
+func writeSchema(out io.Writer, name string) error {
+	project, err := openProject()
+	if err != nil {
+		return err
+	}
+	opts, err := options()
+	if err != nil {
+		return err
+	}
+	template, err := project.FindComponent(name)
+	if err != nil {
+		return err
+	}
+	c, err := project.Component(template, opts)
+	if err != nil {
+		return err
+	}
+
+	b, err := marshalSchema(c)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
+
+func marshalSchema(c *gitlab.Component) ([]byte, error) {
+	b, err := json.MarshalIndent(c.JSONSchema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/peschmae/glab-component-generator/pkg/gitlab"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewValidateUsageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "validate-usage [file...]",
+		Aliases: []string{"v"},
+		Short:   "Validates the inputs passed to the components of the given project in a CI configuration",
+		Long: `Finds all entries of 'include: - component:' in the given CI configuration files,
+which point to a component in <project>/templates, and validates the inputs passed
+to it against the inputs spec of the component.
+
+The following is reported
+  - mandatory inputs which are missing
+  - inputs which don't exist
+  - values which are not one of the options
+  - values which don't match the regex
+  - values which don't match the type
+
+If --usage-project is given, only includes of that project are validated and
+unknown components are reported. Otherwise all includes with the name of a
+component in the project are validated.
+
+Without files, .gitlab-ci.yml is validated. The command fails if at least one
+error was found.
+
+With --report-format codequality the findings are written as GitLab Code Quality
+report, which shows them inline in merge requests. With --report-format sarif
+they are written as SARIF 2.1.0.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{".gitlab-ci.yml"}
+			}
+			return validateUsage(cmd.OutOrStdout(), args)
+		},
+	}
+
+	addReportFlags(cmd)
+	cmd.Flags().String("usage-project", "", "The path of the component project used in the includes, eg. my-group/my-components")
+
+	return cmd
+}
+
+func validateUsage(out io.Writer, files []string) error {
+	components, err := loadComponents()
+	if err != nil {
+		return err
+	}
+
+	findings := []gitlab.Finding{}
+	errs := []error{}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			if !viper.GetBool("keep-going") {
+				return err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		findings = append(findings, gitlab.ValidateUsage(file, b, components, viper.GetString("usage-project"))...)
+	}
+
+	errs = append(errs, reportFindings(out, findings, fmt.Sprintf("%d file(s)", len(files)), "."))
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing/fstest"
+)
+
+// IsArchive returns true if the path is an archive supported by Open
+func IsArchive(name string) bool {
+	return strings.HasSuffix(name, ".zip") || strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// Open returns the content of a .zip or .tar.gz archive as file system. If all
+// files are within a single top level directory, as in GitLab release archives,
+// that directory is the root of the file system.
+func Open(name string) (fs.FS, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var fsys fs.FS
+	if strings.HasSuffix(name, ".zip") {
+		fsys, err = zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	} else if IsArchive(name) {
+		fsys, err = readTarGz(bytes.NewReader(b))
+	} else {
+		return nil, fmt.Errorf("%s: unsupported archive, expected .zip or .tar.gz", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return stripTopLevel(fsys)
+}
+
+// readTarGz reads all files of a gzipped tar archive into memory
+func readTarGz(r io.Reader) (fs.FS, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	fsys := fstest.MapFS{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return fsys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			fsys[name] = &fstest.MapFile{Mode: fs.ModeDir | 0755}
+		case tar.TypeReg:
+			b, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			fsys[name] = &fstest.MapFile{Data: b, Mode: 0644, ModTime: header.ModTime}
+		}
+	}
+}
+
+// stripTopLevel returns the single top level directory of the file system, if
+// there is no other entry
+func stripTopLevel(fsys fs.FS) (fs.FS, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() || entries[0].Name() == "templates" {
+		return fsys, nil
+	}
+	return fs.Sub(fsys, entries[0].Name())
+}
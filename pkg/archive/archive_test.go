@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var files = map[string]string{
+	"project-1.0.0/README.md":                    "# Components\n",
+	"project-1.0.0/templates/build/template.yml": "spec:\n  inputs:\n    stage:\n",
+}
+
+func writeTarGz(name string) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	os.WriteFile(name, buf.Bytes(), 0644)
+}
+
+func writeZip(name string) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	os.WriteFile(name, buf.Bytes(), 0644)
+}
+
+func Test_Open(t *testing.T) {
+	dir := t.TempDir()
+	writeTarGz(filepath.Join(dir, "project.tar.gz"))
+	writeZip(filepath.Join(dir, "project.zip"))
+
+	for _, name := range []string{"project.tar.gz", "project.zip"} {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, IsArchive(name))
+
+			fsys, err := Open(filepath.Join(dir, name))
+			assert.NoError(t, err)
+
+			b, err := fs.ReadFile(fsys, "templates/build/template.yml")
+			assert.NoError(t, err)
+			assert.Equal(t, "spec:\n  inputs:\n    stage:\n", string(b))
+
+			entries, err := fs.ReadDir(fsys, ".")
+			assert.NoError(t, err)
+			assert.Len(t, entries, 2)
+		})
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		name := filepath.Join(dir, "project.rar")
+		os.WriteFile(name, []byte("rar"), 0644)
+
+		assert.False(t, IsArchive(name))
+		_, err := Open(name)
+		assert.ErrorContains(t, err, "unsupported archive")
+	})
+
+	t.Run("Broken", func(t *testing.T) {
+		name := filepath.Join(dir, "broken.tar.gz")
+		os.WriteFile(name, []byte("not gzipped"), 0644)
+
+		_, err := Open(name)
+		assert.ErrorContains(t, err, name)
+	})
+}
@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Unified(t *testing.T) {
+	t.Run("Equal", func(t *testing.T) {
+		assert.Equal(t, "", Unified("a", "b", "line 1\nline 2\n", "line 1\nline 2\n"))
+	})
+
+	t.Run("Changed line", func(t *testing.T) {
+		a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+		b := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+
+		expected := `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`
+		assert.Equal(t, expected, Unified("a", "b", a, b))
+	})
+
+	t.Run("Missing newline at end", func(t *testing.T) {
+		expected := `--- a
++++ b
+@@ -1,2 +1,2 @@
+ 1
+-2
+\ No newline at end of file
++2
+`
+		assert.Equal(t, expected, Unified("a", "b", "1\n2", "1\n2\n"))
+
+		expected = `--- a
++++ b
+@@ -1 +1 @@
+-1
++1
+\ No newline at end of file
+`
+		assert.Equal(t, expected, Unified("a", "b", "1\n", "1"))
+	})
+
+	t.Run("Separate hunks", func(t *testing.T) {
+		a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+		b := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+
+		expected := `--- a
++++ b
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -7,4 +8,3 @@
+ 7
+ 8
+ 9
+-10
+`
+		assert.Equal(t, expected, Unified("a", "b", a, b))
+	})
+
+	t.Run("Empty original", func(t *testing.T) {
+		expected := `--- a
++++ b
+@@ -0,0 +1,2 @@
++1
++2
+`
+		assert.Equal(t, expected, Unified("a", "b", "", "1\n2\n"))
+	})
+}
@@ -5,25 +5,21 @@ package gitlab
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-
-	"github.com/spf13/viper"
-	"gopkg.in/yaml.v3"
+	"text/template"
 )
 
 type ComponentInput struct {
-	Default     string   `yaml:"default"`
-	Description string   `yaml:"description"`
-	Options     []string `yaml:"options"`
-	Type        string   `yaml:"type"`
-	Regex       string   `yaml:"regex"`
-}
-
-func headerLevel() string {
-	return strings.Repeat("#", viper.GetInt("component-header-level"))
+	// Default is nil if the input has no default, which makes it mandatory
+	Default     *Value  `json:"default,omitempty" yaml:"default,omitempty"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Options     []Value `json:"options,omitempty" yaml:"options,omitempty"`
+	Type        string  `json:"type,omitempty" yaml:"type,omitempty"`
+	Regex       string  `json:"regex,omitempty" yaml:"regex,omitempty"`
+	// InheritedFrom is the file the input was included from using spec:include
+	InheritedFrom string `json:"-" yaml:"-"`
 }
 
 // replace linebreaks with <br> as Gitlab converts it to HTML anyways
@@ -31,20 +27,36 @@ func replaceLinebreaks(input string) string {
 	return strings.TrimSuffix(strings.ReplaceAll(input, "\n", "<br>"), "<br>")
 }
 
+// DefaultMarkdown returns the default value, or the mandatory marker if there is none
+func (input ComponentInput) DefaultMarkdown() string {
+	if input.Default == nil {
+		return MandatoryMarker
+	}
+	return input.Default.Markdown()
+}
+
+// OptionsStrings returns the options formatted as strings
+func (input ComponentInput) OptionsStrings() []string {
+	options := make([]string, len(input.Options))
+	for i, option := range input.Options {
+		options[i] = option.String()
+	}
+	return options
+}
+
 func (input ComponentInput) Markdown(name string, hasTypes, hasOptions, hasRegex bool) string {
 	var sb strings.Builder
-	if input.Default == "" {
-		input.Default = fmt.Sprintf("%c", '\U000026D4')
-	} else {
-		input.Default = fmt.Sprintf("_%s_", input.Default)
+	description := replaceLinebreaks(input.Description)
+	if input.InheritedFrom != "" {
+		description = strings.TrimPrefix(fmt.Sprintf("%s<br>_Inherited from `%s`_", description, input.InheritedFrom), "<br>")
 	}
-	sb.WriteString(fmt.Sprintf("| %-16s | %-11s | %-13s |", fmt.Sprintf("`%s`", name), replaceLinebreaks(input.Description), input.Default))
+	sb.WriteString(fmt.Sprintf("| %-16s | %-11s | %-13s |", fmt.Sprintf("`%s`", name), description, input.DefaultMarkdown()))
 
 	if hasTypes {
 		sb.WriteString(fmt.Sprintf(" %-7s |", input.Type))
 	}
 	if hasOptions {
-		sb.WriteString(fmt.Sprintf(" _%s_ |", strings.Join(input.Options, ", ")))
+		sb.WriteString(fmt.Sprintf(" _%s_ |", strings.Join(input.OptionsStrings(), ", ")))
 	}
 	if hasRegex {
 		sb.WriteString(fmt.Sprintf(" `%s` |", input.Regex))
@@ -56,7 +68,25 @@ func (input ComponentInput) Markdown(name string, hasTypes, hasOptions, hasRegex
 }
 
 type ComponentSpec struct {
-	Inputs map[string]ComponentInput `yaml:"inputs"`
+	Include []SpecInclude             `yaml:"include"`
+	Inputs  map[string]ComponentInput `yaml:"inputs"`
+}
+
+type NamedInput struct {
+	Name           string `json:"name" yaml:"name"`
+	ComponentInput `yaml:",inline"`
+}
+
+// SortedInputs returns the inputs sorted by their name
+func (spec *ComponentSpec) SortedInputs() []NamedInput {
+	inputs := make([]NamedInput, 0, len(spec.Inputs))
+	for name, input := range spec.Inputs {
+		inputs = append(inputs, NamedInput{Name: name, ComponentInput: input})
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].Name < inputs[j].Name
+	})
+	return inputs
 }
 
 func (spec *ComponentSpec) MarkdownTable() string {
@@ -87,18 +117,8 @@ func (spec *ComponentSpec) MarkdownTable() string {
 	sb.WriteString(divider.String())
 	sb.WriteString("\n")
 
-	keys := make([]string, len(spec.Inputs))
-
-	i := 0
-	for k := range spec.Inputs {
-		keys[i] = k
-		i++
-	}
-
-	sort.Strings(keys)
-
-	for i = 0; i < len(keys); i++ {
-		sb.WriteString(spec.Inputs[keys[i]].Markdown(keys[i], hasTypes, hasOptions, hasRegex))
+	for _, input := range spec.SortedInputs() {
+		sb.WriteString(input.Markdown(input.Name, hasTypes, hasOptions, hasRegex))
 	}
 
 	return sb.String()
@@ -136,71 +156,75 @@ func (spec *ComponentSpec) HasRegex() bool {
 }
 
 type Component struct {
-	Name   string
-	Header string
-	Footer string
+	Name string `yaml:"-"`
+	// Path of the component template
+	Path   string         `yaml:"-"`
+	Header string         `yaml:"-"`
+	Footer string         `yaml:"-"`
 	Spec   *ComponentSpec `yaml:"spec"`
+	// Jobs defined in the documents following the spec
+	Jobs []Job `yaml:"-"`
+	// Template overrides the template used to render the component
+	Template string `yaml:"-"`
+	// Category groups the component in the README
+	Category string `yaml:"-"`
+	// Description is a one-line summary of the component
+	Description string `yaml:"-"`
 }
 
-func (c *Component) Markdown() string {
+// Markdown renders the component using the default template
+func (c *Component) Markdown(opts Options) string {
+	// the default template is known to work with every component
+	md, _ := c.Render(defaultTemplate, opts)
+	return md
+}
 
-	if c.Header == "" && c.Footer == "" && c.Spec == nil {
-		return ""
-	}
+// IsDirectory returns true if the component has its own directory within templates/
+func (c *Component) IsDirectory() bool {
+	return isDirectoryTemplate(c.Path)
+}
 
-	var md strings.Builder
+func isDirectoryTemplate(path string) bool {
+	return filepath.Base(path) == "template.yml" || filepath.Base(path) == "template.yaml"
+}
 
-	// render component header
-	md.WriteString(fmt.Sprintf("%s %s\n\n", headerLevel(), c.Name))
+// Render renders the component using the given template
+func (c *Component) Render(tmpl *template.Template, opts Options) (string, error) {
 
-	if c.Header != "" {
-		md.WriteString(strings.TrimSpace(c.Header) + "\n\n")
+	if c.Header == "" && c.Footer == "" && c.Spec == nil && len(c.Jobs) == 0 {
+		return "", nil
 	}
 
-	if c.Spec != nil {
-		md.WriteString(c.Spec.MarkdownTable() + "\n")
+	// the functions depending on the options are bound for this execution only
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", err
 	}
+	tmpl.Funcs(opts.templateFuncs())
 
-	if c.Footer != "" {
-		md.WriteString(strings.TrimSpace(c.Footer) + "\n")
+	var md strings.Builder
+	if err := tmpl.Execute(&md, c); err != nil {
+		return "", fmt.Errorf("component %s: %w", c.Name, err)
 	}
 
-	return md.String()
+	return md.String(), nil
 }
 
-func NewComponent(path string) (*Component, error) {
-	var name string
-	var header []byte
-	var footer []byte
-	// GitLab allows yaml files directly in template directory, there we need to get the name from the filename
-	// Otherwise the name is the parent directory name
-	if filepath.Base(path) == "template.yml" || filepath.Base(path) == "template.yaml" {
-		name = filepath.Base(filepath.Dir(path))
-
-		if _, err := os.Stat(filepath.Join(filepath.Dir(path), viper.GetString("component-header"))); err == nil {
-			header, err = os.ReadFile(filepath.Join(filepath.Dir(path), viper.GetString("component-header")))
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		if _, err := os.Stat(filepath.Join(filepath.Dir(path), viper.GetString("component-footer"))); err == nil {
-			footer, err = os.ReadFile(filepath.Join(filepath.Dir(path), viper.GetString("component-footer")))
-			if err != nil {
-				return nil, err
-			}
-		}
-	} else {
-		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+// FindComponents returns the paths of all component templates within <project>/templates
+func FindComponents(project string) ([]string, error) {
+	components, err := NewProject(project).FindComponents()
+	for i := range components {
+		components[i] = filepath.Join(project, filepath.FromSlash(components[i]))
 	}
+	return components, err
+}
 
-	c := &Component{Name: name, Header: string(header), Footer: string(footer)}
-
-	b, err := os.ReadFile(path)
+// NewComponent reads the component from the given template file on disk. The
+// header, footer and template of directory components are read using the options.
+func NewComponent(path string, opts Options) (*Component, error) {
+	project, template, err := splitProject(path)
 	if err != nil {
 		return nil, err
 	}
-	yaml.Unmarshal(b, c)
-
-	return c, nil
+	return project.Component(template, opts)
 }
@@ -2,12 +2,13 @@ package gitlab
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
 
-	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,9 +35,9 @@ inputs:
 		expected.WriteString(`| Input / Variable | Description | Default value | Type    | Options | Regex |
 | ---------------- | ----------- | ------------- | ------- | ------- | ----- |
 `)
-		expected.WriteString("| `concurrency`    |             | _1_           | number  | __ | `` |\n")
+		expected.WriteString("| `concurrency`    |             | `1`           | number  | __ | `` |\n")
 		expected.WriteString(fmt.Sprintf("| `environment`    |             | %c             |         | _test, staging, production_ | `` |\n", '\U000026D4'))
-		expected.WriteString("| `export_results` |             | _true_        | boolean | __ | `` |\n")
+		expected.WriteString("| `export_results` |             | `true`        | boolean | __ | `` |\n")
 		expected.WriteString(fmt.Sprintf("| `job-prefix`     | Define a prefix for the job name | %c             |         | __ | `` |\n", '\U000026D4'))
 		expected.WriteString("| `job-stage`      |             | _test_        |         | __ | `` |\n")
 		expected.WriteString(fmt.Sprintf("| `version`        |             | %c             | string  | __ | `/^v\\d\\.\\d+(\\.\\d+)$/` |\n", '\U000026D4'))
@@ -68,8 +69,8 @@ inputs:
 		expected.WriteString(`| Input / Variable | Description | Default value | Type    | Regex |
 | ---------------- | ----------- | ------------- | ------- | ----- |
 `)
-		expected.WriteString("| `concurrency`    |             | _1_           | number  | `` |\n")
-		expected.WriteString("| `export_results` |             | _true_        | boolean | `` |\n")
+		expected.WriteString("| `concurrency`    |             | `1`           | number  | `` |\n")
+		expected.WriteString("| `export_results` |             | `true`        | boolean | `` |\n")
 		expected.WriteString(fmt.Sprintf("| `job-prefix`     | Define a prefix for the job name | %c             |         | `` |\n", '\U000026D4'))
 		expected.WriteString("| `job-stage`      |             | _test_        |         | `` |\n")
 		expected.WriteString(fmt.Sprintf("| `version`        |             | %c             | string  | `/^v\\d\\.\\d+(\\.\\d+)$/` |\n", '\U000026D4'))
@@ -100,9 +101,9 @@ inputs:
 		expected.WriteString(`| Input / Variable | Description | Default value | Type    | Options |
 | ---------------- | ----------- | ------------- | ------- | ------- |
 `)
-		expected.WriteString("| `concurrency`    |             | _1_           | number  | __ |\n")
+		expected.WriteString("| `concurrency`    |             | `1`           | number  | __ |\n")
 		expected.WriteString(fmt.Sprintf("| `environment`    |             | %c             |         | _test, staging, production_ |\n", '\U000026D4'))
-		expected.WriteString("| `export_results` |             | _true_        | boolean | __ |\n")
+		expected.WriteString("| `export_results` |             | `true`        | boolean | __ |\n")
 		expected.WriteString(fmt.Sprintf("| `job-prefix`     | Define a prefix for the job name | %c             |         | __ |\n", '\U000026D4'))
 		expected.WriteString("| `job-stage`      |             | _test_        |         | __ |\n")
 
@@ -155,8 +156,8 @@ inputs:
 		expected.WriteString(`| Input / Variable | Description | Default value |
 | ---------------- | ----------- | ------------- |
 `)
-		expected.WriteString("| `concurrency`    |             | _1_           |\n")
-		expected.WriteString("| `export_results` |             | _true_        |\n")
+		expected.WriteString("| `concurrency`    |             | `1`           |\n")
+		expected.WriteString("| `export_results` |             | `true`        |\n")
 		expected.WriteString(fmt.Sprintf("| `job-prefix`     | Define a prefix for the job name | %c             |\n", '\U000026D4'))
 		expected.WriteString("| `job-stage`      |             | _test_        |\n")
 
@@ -167,6 +168,31 @@ inputs:
 
 	})
 
+	t.Run("Non-string defaults", func(t *testing.T) {
+		input := `
+inputs:
+  empty:
+    default: ''
+  paths:
+    type: array
+    default: ['a', 'b']
+  mandatory:
+    type: array`
+		var expected strings.Builder
+		expected.WriteString(`| Input / Variable | Description | Default value | Type    |
+| ---------------- | ----------- | ------------- | ------- |
+`)
+		expected.WriteString("| `empty`          |             | \"\"            |         |\n")
+		expected.WriteString(fmt.Sprintf("| `mandatory`      |             | %c             | array   |\n", '\U000026D4'))
+		expected.WriteString("| `paths`          |             | `[\"a\",\"b\"]`   | array   |\n")
+
+		spec := &ComponentSpec{}
+		yaml.Unmarshal([]byte(input), spec)
+
+		assert.Equal(t, expected.String(), spec.MarkdownTable())
+
+	})
+
 	t.Run("Description linebreak", func(t *testing.T) {
 		input := `
 inputs:
@@ -179,7 +205,7 @@ inputs:
 		expected.WriteString(`| Input / Variable | Description | Default value |
 | ---------------- | ----------- | ------------- |
 `)
-		expected.WriteString("| `export_results` | Line 1<br>Line 2 | _true_        |\n")
+		expected.WriteString("| `export_results` | Line 1<br>Line 2 | `true`        |\n")
 
 		spec := &ComponentSpec{}
 		yaml.Unmarshal([]byte(input), spec)
@@ -190,7 +216,7 @@ inputs:
 }
 
 func Test_ComponentMarkdown(t *testing.T) {
-	viper.Set("component-header-level", 2)
+	opts := DefaultOptions()
 
 	input := `
 spec:
@@ -213,7 +239,7 @@ spec:
 		component := &Component{Name: "Component test"}
 		yaml.Unmarshal([]byte(input), component)
 
-		assert.Equal(t, expected.String(), component.Markdown())
+		assert.Equal(t, expected.String(), component.Markdown(opts))
 
 	})
 
@@ -234,7 +260,7 @@ Some Header
 		component := &Component{Name: "Header test", Header: "Some Header"}
 		yaml.Unmarshal([]byte(input), component)
 
-		assert.Equal(t, expected.String(), component.Markdown())
+		assert.Equal(t, expected.String(), component.Markdown(opts))
 
 	})
 
@@ -256,7 +282,7 @@ Header
 		component := &Component{Name: "Header test", Header: "Some\nHeader\n"}
 		yaml.Unmarshal([]byte(input), component)
 
-		assert.Equal(t, expected.String(), component.Markdown())
+		assert.Equal(t, expected.String(), component.Markdown(opts))
 
 	})
 
@@ -275,13 +301,54 @@ Header
 		component := &Component{Name: "Footer test", Footer: "Some Footer"}
 		yaml.Unmarshal([]byte(input), component)
 
-		assert.Equal(t, expected.String(), component.Markdown())
+		assert.Equal(t, expected.String(), component.Markdown(opts))
+
+	})
+
+	t.Run("Jobs", func(t *testing.T) {
+
+		var expected strings.Builder
+		expected.WriteString(`## Jobs test
+
+| Input / Variable | Description | Default value |
+| ---------------- | ----------- | ------------- |
+`)
+		expected.WriteString(fmt.Sprintf("| `job-prefix`     | Define a prefix for the job name | %c             |\n", '\U000026D4'))
+
+		expected.WriteString("\nThis component adds the following jobs:\n\n| Job | Stage |\n| --- | ----- |\n| `build` | build |\n\n")
+
+		component := &Component{Name: "Jobs test", Jobs: []Job{{Name: "build", Stage: "build"}}}
+		yaml.Unmarshal([]byte(input), component)
+
+		assert.Equal(t, expected.String(), component.Markdown(opts))
+
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.Usage = &UsageOptions{Project: "group/project", Version: "1.0.0"}
+
+		var expected strings.Builder
+		expected.WriteString(`## Usage test
+
+| Input / Variable | Description | Default value |
+| ---------------- | ----------- | ------------- |
+`)
+		expected.WriteString(fmt.Sprintf("| `job-prefix`     | Define a prefix for the job name | %c             |\n", '\U000026D4'))
+
+		expected.WriteString("\n```yaml\ninclude:\n  - component: $CI_SERVER_FQDN/group/project/Usage test@1.0.0\n    inputs:\n      job-prefix: <job-prefix>\n```\n\n")
+
+		component := &Component{Name: "Usage test"}
+		yaml.Unmarshal([]byte(input), component)
+
+		assert.Equal(t, expected.String(), component.Markdown(opts))
 
 	})
 
 	t.Run("Header component level", func(t *testing.T) {
 
-		viper.Set("component-header-level", 3)
+		opts := DefaultOptions()
+		opts.HeaderLevel = 3
 
 		var expected strings.Builder
 		expected.WriteString(`### Header level test
@@ -296,8 +363,31 @@ Header
 		component := &Component{Name: "Header level test"}
 		yaml.Unmarshal([]byte(input), component)
 
-		assert.Equal(t, expected.String(), component.Markdown())
+		assert.Equal(t, expected.String(), component.Markdown(opts))
 
 	})
 
 }
+
+func Test_NewComponent(t *testing.T) {
+	t.Run("Multiple documents", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "component.yml")
+		os.WriteFile(path, []byte(`spec:
+  inputs:
+    stage:
+      default: test
+---
+build:
+  stage: $[[ inputs.stage ]]
+  script: make
+`), 0644)
+
+		c, err := NewComponent(path, DefaultOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, "component", c.Name)
+		assert.Equal(t, path, c.Path)
+		assert.False(t, c.IsDirectory())
+		assert.Equal(t, "test", c.Spec.Inputs["stage"].Default.String())
+		assert.Equal(t, []Job{{Name: "build", Stage: "$[[ inputs.stage ]]"}}, c.Jobs)
+	})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"strings"
+)
+
+// Options configure how components are loaded and rendered
+type Options struct {
+	// ComponentHeader is the file prepended to a component, relative to its directory
+	ComponentHeader string
+	// ComponentFooter is the file appended to a component, relative to its directory
+	ComponentFooter string
+	// ComponentTemplate is the template overriding the template of a component,
+	// relative to its directory
+	ComponentTemplate string
+	// Template is the template used to render the component, relative to the
+	// project. It is not read by this package, but can be overridden per component.
+	Template string
+	// HeaderFromComment uses the comment block at the top of a template as
+	// header, if the component has no header file
+	HeaderFromComment bool
+	// HeaderLevel is the level of the heading of each component
+	HeaderLevel int
+	// Usage renders an include snippet for each component, if set
+	Usage *UsageOptions
+	// Sort is the order of the components, either SortByPath or SortByName
+	Sort string
+	// Order lists component names which come first, in the given order
+	Order []string
+	// Components overrides options per component name
+	Components map[string]ComponentOverrides
+}
+
+// ComponentOverrides replace the options of a single component. A nil value
+// keeps the global option.
+type ComponentOverrides struct {
+	ComponentHeader   *string `mapstructure:"component-header"`
+	ComponentFooter   *string `mapstructure:"component-footer"`
+	ComponentTemplate *string `mapstructure:"component-template"`
+	Template          *string `mapstructure:"template"`
+	// Category groups the component, it takes precedence over the front matter
+	Category *string `mapstructure:"category"`
+}
+
+// DefaultOptions returns the options used by the CLI, if no flags are given
+func DefaultOptions() Options {
+	return Options{
+		ComponentHeader:   "HEADER.md",
+		ComponentFooter:   "FOOTER.md",
+		ComponentTemplate: "README.md.tmpl",
+		HeaderLevel:       2,
+		Sort:              SortByPath,
+	}
+}
+
+// For returns the options with the overrides of the given component applied
+func (o Options) For(name string) Options {
+	overrides := o.overrides(name)
+	if overrides.ComponentHeader != nil {
+		o.ComponentHeader = *overrides.ComponentHeader
+	}
+	if overrides.ComponentFooter != nil {
+		o.ComponentFooter = *overrides.ComponentFooter
+	}
+	if overrides.ComponentTemplate != nil {
+		o.ComponentTemplate = *overrides.ComponentTemplate
+	}
+	if overrides.Template != nil {
+		o.Template = *overrides.Template
+	}
+	return o
+}
+
+// overrides returns the overrides of the given component. Component names are
+// matched case-insensitive, as config file keys are usually lowercased.
+func (o Options) overrides(name string) ComponentOverrides {
+	for key, overrides := range o.Components {
+		if strings.EqualFold(key, name) {
+			return overrides
+		}
+	}
+	return ComponentOverrides{}
+}
@@ -0,0 +1,24 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OptionsFor(t *testing.T) {
+	intro := "INTRO.md"
+	empty := ""
+	options := DefaultOptions()
+	options.Components = map[string]ComponentOverrides{
+		"build": {ComponentHeader: &intro, ComponentFooter: &empty},
+	}
+
+	build := options.For("Build")
+	assert.Equal(t, "INTRO.md", build.ComponentHeader)
+	assert.Equal(t, "", build.ComponentFooter)
+	assert.Equal(t, "README.md.tmpl", build.ComponentTemplate)
+	assert.Equal(t, 2, build.HeaderLevel)
+
+	assert.Equal(t, options, options.For("deploy"))
+}
@@ -0,0 +1,38 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"regexp"
+	"strings"
+)
+
+// commentDirective matches comments which are no description, like directives
+// for editors and linters or license notices
+var commentDirective = regexp.MustCompile(`(?i)^(yaml-language-server:|yamllint |prettier-ignore|vim?:|-\*-|SPDX-|copyright\b|\(c\)|©|license)`)
+
+// leadingComment returns the comment block at the top of a template, without
+// the leading #. The block ends at the first line which is not a comment.
+// Directives for editors and linters, eg. # yaml-language-server: $schema=...,
+// and license notices are skipped.
+func leadingComment(b []byte) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		line = strings.TrimPrefix(line, "#")
+		line = strings.TrimPrefix(line, " ")
+		if commentDirective.MatchString(line) {
+			continue
+		}
+		sb.WriteString(line + "\n")
+	}
+	comment := strings.TrimSpace(sb.String())
+	if comment == "" {
+		return ""
+	}
+	return comment + "\n"
+}
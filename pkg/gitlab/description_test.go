@@ -0,0 +1,29 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_leadingComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Comment block", "# Builds the project.\n#\n# Uses Kaniko.\nspec:\n  inputs:\n", "Builds the project.\n\nUses Kaniko.\n"},
+		{"Indented markdown", "#   - item\n#\tcode\nspec:\n", "- item\n\tcode\n"},
+		{"Editor directive", "# yaml-language-server: $schema=schema.json\n# Builds the project\nspec:\n", "Builds the project\n"},
+		{"Linter and license", "# Copyright 2024 ACME Corp. All rights reserved.\n# SPDX-License-Identifier: Apache-2.0\n# yamllint disable rule:line-length\n# vim: set ft=yaml:\n# Builds the project\nspec:\n", "Builds the project\n"},
+		{"Ends at first line", "# Builds the project\n\n# inputs of the component\nspec:\n", "Builds the project\n"},
+		{"Without comment", "spec:\n  inputs: # comment\n", ""},
+		{"Empty comment", "#\n#\nspec:\n", ""},
+		{"Windows line endings", "# Builds\r\n# the project\r\nspec:\r\n", "Builds\nthe project\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, leadingComment([]byte(tt.input)))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileError is an error reading or parsing a file, with the location within
+// the file if it is known
+type FileError struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *FileError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	default:
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// NewFileError returns the error with the location within the file, the same
+// way errors of templates are reported
+func NewFileError(file string, err error) error {
+	return fileError(file, err)
+}
+
+// typeErrorLine extracts the line from a single error of a *yaml.TypeError
+var typeErrorLine = regexp.MustCompile(`^line (\d+): `)
+
+// fileError returns the error with the location within the file. Errors of the
+// yaml parser contain the line, a *yaml.TypeError is split into one error per line.
+func fileError(file string, err error) error {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		errs := make([]error, len(typeErr.Errors))
+		for i, e := range typeErr.Errors {
+			errs[i] = &FileError{File: file, Err: errors.New(e)}
+			if m := typeErrorLine.FindStringSubmatch(e); m != nil {
+				line, _ := strconv.Atoi(m[1])
+				errs[i] = &FileError{File: file, Line: line, Err: errors.New(strings.TrimPrefix(e, m[0]))}
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &FileError{File: file, Line: line, Err: errors.New(strings.TrimPrefix(err.Error(), m[0]))}
+	}
+
+	// errors on the first line have no line number
+	if msg, ok := strings.CutPrefix(err.Error(), "yaml: "); ok {
+		return &FileError{File: file, Err: errors.New(msg)}
+	}
+
+	// the path is already part of the file
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return &FileError{File: file, Err: fmt.Errorf("%s: %w", pathErr.Op, pathErr.Err)}
+	}
+	return &FileError{File: file, Err: err}
+}
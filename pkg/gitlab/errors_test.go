@@ -0,0 +1,44 @@
+package gitlab
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_FileError(t *testing.T) {
+	t.Run("Location", func(t *testing.T) {
+		err := errors.New("broken")
+		assert.EqualError(t, &FileError{File: "a.yml", Err: err}, "a.yml: broken")
+		assert.EqualError(t, &FileError{File: "a.yml", Line: 3, Err: err}, "a.yml:3: broken")
+		assert.EqualError(t, &FileError{File: "a.yml", Line: 3, Column: 5, Err: err}, "a.yml:3:5: broken")
+		assert.ErrorIs(t, &FileError{File: "a.yml", Err: err}, err)
+	})
+
+	t.Run("Syntax error", func(t *testing.T) {
+		var v any
+		err := fileError("a.yml", yaml.Unmarshal([]byte("a:\n  b: c: d\n"), &v))
+		assert.EqualError(t, err, "a.yml:2: mapping values are not allowed in this context")
+
+		err = fileError("a.yml", yaml.Unmarshal([]byte("a: b: c\n"), &v))
+		assert.EqualError(t, err, "a.yml: mapping values are not allowed in this context")
+	})
+
+	t.Run("Type error", func(t *testing.T) {
+		var v struct {
+			A string
+			B []string
+		}
+		err := fileError("a.yml", yaml.Unmarshal([]byte("a: [x]\nb: y\n"), &v))
+		assert.EqualError(t, err, "a.yml:1: cannot unmarshal !!seq into string\na.yml:2: cannot unmarshal !!str `y` into []string")
+	})
+
+	t.Run("IO error", func(t *testing.T) {
+		err := fileError("project/a.yml", &fs.PathError{Op: "open", Path: "a.yml", Err: fs.ErrNotExist})
+		assert.EqualError(t, err, "project/a.yml: open: file does not exist")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
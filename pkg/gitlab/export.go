@@ -0,0 +1,83 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportedComponent is the machine-readable description of a component
+type ExportedComponent struct {
+	Name string `json:"name" yaml:"name"`
+	// Path of the template, relative to the project
+	Path     string          `json:"path" yaml:"path"`
+	Category string          `json:"category,omitempty" yaml:"category,omitempty"`
+	Header   string          `json:"header,omitempty" yaml:"header,omitempty"`
+	Footer   string          `json:"footer,omitempty" yaml:"footer,omitempty"`
+	Inputs   []ExportedInput `json:"inputs" yaml:"inputs"`
+	Jobs     []Job           `json:"jobs" yaml:"jobs"`
+}
+
+type ExportedInput struct {
+	NamedInput `yaml:",inline"`
+	// Mandatory is true if the input has no default
+	Mandatory     bool   `json:"mandatory" yaml:"mandatory"`
+	InheritedFrom string `json:"inherited_from,omitempty" yaml:"inherited_from,omitempty"`
+}
+
+// Export returns the machine-readable description of the components
+func Export(project string, components []*Component) []ExportedComponent {
+	exported := make([]ExportedComponent, len(components))
+	for i, c := range components {
+		path := c.Path
+		if rel, err := filepath.Rel(project, c.Path); err == nil {
+			path = filepath.ToSlash(rel)
+		}
+
+		exported[i] = ExportedComponent{
+			Name:     c.Name,
+			Path:     path,
+			Category: c.Category,
+			Header:   c.Header,
+			Footer:   c.Footer,
+			Inputs:   []ExportedInput{},
+			Jobs:     c.Jobs,
+		}
+		if c.Spec != nil {
+			for _, input := range c.Spec.SortedInputs() {
+				exported[i].Inputs = append(exported[i].Inputs, ExportedInput{NamedInput: input, Mandatory: input.Default == nil, InheritedFrom: input.InheritedFrom})
+			}
+		}
+		if exported[i].Jobs == nil {
+			exported[i].Jobs = []Job{}
+		}
+	}
+	return exported
+}
+
+// Marshal encodes the exported components in the given format, either json or yaml
+func Marshal(components []ExportedComponent, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(components, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case "yaml":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(components); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q, supported are json and yaml", format)
+}
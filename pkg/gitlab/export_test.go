@@ -0,0 +1,117 @@
+package gitlab
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Export(t *testing.T) {
+	component := &Component{
+		Name:   "deploy",
+		Path:   filepath.Join("project", "templates", "deploy", "template.yml"),
+		Header: "Deploys things",
+		Jobs:   []Job{{Name: "deploy", Stage: "deploy"}},
+	}
+	yaml.Unmarshal([]byte(`
+spec:
+  inputs:
+    environment:
+      options: ['test', 'production']
+    paths:
+      type: array
+      default: [a, b]
+    empty:
+      default: ''`), component)
+
+	exported := Export("project", []*Component{component, {Name: "empty", Path: filepath.Join("project", "templates", "empty.yml")}})
+
+	t.Run("JSON", func(t *testing.T) {
+		expected := `[
+  {
+    "name": "deploy",
+    "path": "templates/deploy/template.yml",
+    "header": "Deploys things",
+    "inputs": [
+      {
+        "name": "empty",
+        "default": "",
+        "mandatory": false
+      },
+      {
+        "name": "environment",
+        "options": [
+          "test",
+          "production"
+        ],
+        "mandatory": true
+      },
+      {
+        "name": "paths",
+        "default": [
+          "a",
+          "b"
+        ],
+        "type": "array",
+        "mandatory": false
+      }
+    ],
+    "jobs": [
+      {
+        "name": "deploy",
+        "stage": "deploy"
+      }
+    ]
+  },
+  {
+    "name": "empty",
+    "path": "templates/empty.yml",
+    "inputs": [],
+    "jobs": []
+  }
+]
+`
+		b, err := Marshal(exported, "json")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(b))
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		expected := `- name: deploy
+  path: templates/deploy/template.yml
+  header: Deploys things
+  inputs:
+    - name: empty
+      default: ""
+      mandatory: false
+    - name: environment
+      options:
+        - test
+        - production
+      mandatory: true
+    - name: paths
+      default:
+        - a
+        - b
+      type: array
+      mandatory: false
+  jobs:
+    - name: deploy
+      stage: deploy
+- name: empty
+  path: templates/empty.yml
+  inputs: []
+  jobs: []
+`
+		b, err := Marshal(exported, "yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(b))
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := Marshal(exported, "xml")
+		assert.EqualError(t, err, `unsupported format "xml", supported are json and yaml`)
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter is the YAML block at the beginning of the header of a component,
+// enclosed by lines containing only ---
+type FrontMatter struct {
+	// Category groups the component in the README
+	Category string `yaml:"category"`
+	// Description is a one-line summary of the component
+	Description string `yaml:"description"`
+}
+
+// splitFrontMatter returns the front matter and the content following it. If
+// there is no front matter, the content is returned unchanged.
+func splitFrontMatter(b []byte) (FrontMatter, []byte, error) {
+	var fm FrontMatter
+	normalized := bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(normalized, []byte("---\n")) {
+		return fm, b, nil
+	}
+
+	rest := normalized[len("---\n"):]
+	var block []byte
+	if bytes.HasPrefix(rest, []byte("---\n")) || bytes.Equal(rest, []byte("---")) {
+		// the front matter is empty
+		block, rest = nil, bytes.TrimPrefix(rest, []byte("---"))
+	} else {
+		end := bytes.Index(rest, []byte("\n---\n"))
+		if end < 0 {
+			if !bytes.HasSuffix(rest, []byte("\n---")) {
+				// no closing line, so this is a horizontal rule
+				return fm, b, nil
+			}
+			end = len(rest) - len("\n---")
+		}
+		block = rest[:end]
+		rest = rest[min(end+len("\n---\n"), len(rest)):]
+	}
+
+	if err := yaml.Unmarshal(block, &fm); err != nil {
+		return fm, b, err
+	}
+	return fm, bytes.TrimPrefix(rest, []byte("\n")), nil
+}
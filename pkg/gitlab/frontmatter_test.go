@@ -0,0 +1,37 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_splitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected FrontMatter
+		content  string
+	}{
+		{"Without front matter", "Builds the project\n", FrontMatter{}, "Builds the project\n"},
+		{"Front matter", "---\ncategory: Build\n---\nBuilds the project\n", FrontMatter{Category: "Build"}, "Builds the project\n"},
+		{"Blank line after front matter", "---\ncategory: Build\n---\n\nBuilds the project\n", FrontMatter{Category: "Build"}, "Builds the project\n"},
+		{"Only front matter", "---\ncategory: Build\n---", FrontMatter{Category: "Build"}, ""},
+		{"Empty front matter", "---\n---\nBuilds the project\n", FrontMatter{}, "Builds the project\n"},
+		{"Windows line endings", "---\r\ncategory: Build\r\n---\r\nBuilds the project\r\n", FrontMatter{Category: "Build"}, "Builds the project\n"},
+		{"Horizontal rule", "---\nBuilds the project\n", FrontMatter{}, "---\nBuilds the project\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, content, err := splitFrontMatter([]byte(tt.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, fm)
+			assert.Equal(t, tt.content, string(content))
+		})
+	}
+
+	t.Run("Invalid front matter", func(t *testing.T) {
+		_, _, err := splitFrontMatter([]byte("---\ncategory: [a\n---\n"))
+		assert.Error(t, err)
+	})
+}
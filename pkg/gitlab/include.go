@@ -0,0 +1,75 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecInclude is an external file with input definitions, included using spec:include
+type SpecInclude struct {
+	Local string `yaml:"local"`
+}
+
+// inputsFile is the content of an included file, which can either contain
+// the inputs directly or within a spec
+type inputsFile struct {
+	Inputs map[string]ComponentInput `yaml:"inputs"`
+	Spec   *ComponentSpec            `yaml:"spec"`
+}
+
+// projectRoot returns the root of the project a component template belongs to
+func projectRoot(path string) string {
+	templates := filepath.Dir(path)
+	if isDirectoryTemplate(path) {
+		templates = filepath.Dir(templates)
+	}
+	return filepath.Dir(templates)
+}
+
+// resolveIncludes merges the inputs of all local spec includes into the spec.
+// Inputs defined in the spec itself take precedence over included inputs. Errors
+// within an included file are reported with its path below root.
+func (spec *ComponentSpec) resolveIncludes(project fs.FS, root string) error {
+	for _, include := range spec.Include {
+		if include.Local == "" {
+			// only local files can be resolved without access to GitLab
+			continue
+		}
+
+		// local includes are always relative to the project root
+		rel := strings.TrimPrefix(include.Local, "/")
+		b, err := fs.ReadFile(project, path.Clean(rel))
+		if err != nil {
+			return fmt.Errorf("spec:include %s: %w", include.Local, err)
+		}
+
+		var file inputsFile
+		if err := yaml.Unmarshal(b, &file); err != nil {
+			return fileError(filepath.Join(root, filepath.FromSlash(rel)), err)
+		}
+		inputs := file.Inputs
+		if file.Spec != nil {
+			inputs = file.Spec.Inputs
+		}
+
+		if spec.Inputs == nil && len(inputs) > 0 {
+			spec.Inputs = map[string]ComponentInput{}
+		}
+		for name, input := range inputs {
+			if _, ok := spec.Inputs[name]; ok {
+				continue
+			}
+			input.InheritedFrom = rel
+			spec.Inputs[name] = input
+		}
+	}
+	return nil
+}
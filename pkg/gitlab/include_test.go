@@ -0,0 +1,101 @@
+package gitlab
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SpecInclude(t *testing.T) {
+	project := t.TempDir()
+	os.MkdirAll(filepath.Join(project, "templates", "build"), 0755)
+	os.MkdirAll(filepath.Join(project, "shared"), 0755)
+
+	os.WriteFile(filepath.Join(project, "shared", "inputs.yml"), []byte(`inputs:
+  stage:
+    default: build
+  image:
+    description: The image
+`), 0644)
+	os.WriteFile(filepath.Join(project, "shared", "spec.yml"), []byte(`spec:
+  inputs:
+    tags:
+      type: array
+      default: []
+`), 0644)
+
+	template := `spec:
+  include:
+    - local: /shared/inputs.yml
+    - local: shared/spec.yml
+    - remote: https://example.com/inputs.yml
+  inputs:
+    image:
+      default: alpine
+---
+build:
+  stage: $[[ inputs.stage ]]
+  image: $[[ inputs.image ]]
+  tags: $[[ inputs.tags ]]
+`
+	path := filepath.Join(project, "templates", "build", "template.yml")
+	os.WriteFile(path, []byte(template), 0644)
+
+	t.Run("Component", func(t *testing.T) {
+		c, err := NewComponent(path, DefaultOptions())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "", c.Spec.Inputs["image"].InheritedFrom)
+		assert.Equal(t, "alpine", c.Spec.Inputs["image"].Default.String())
+		assert.Equal(t, "shared/inputs.yml", c.Spec.Inputs["stage"].InheritedFrom)
+		assert.Equal(t, "shared/spec.yml", c.Spec.Inputs["tags"].InheritedFrom)
+
+		expected := `| Input / Variable | Description | Default value | Type    |
+| ---------------- | ----------- | ------------- | ------- |
+| ` + "`image`" + `          |             | _alpine_      |         |
+| ` + "`stage`" + `          | _Inherited from ` + "`shared/inputs.yml`" + `_ | _build_       |         |
+| ` + "`tags`" + `           | _Inherited from ` + "`shared/spec.yml`" + `_ | ` + "`[]`" + `          | array   |
+`
+		assert.Equal(t, expected, c.Spec.MarkdownTable())
+	})
+
+	t.Run("Lint", func(t *testing.T) {
+		findings, err := Lint(path)
+		assert.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("Missing include", func(t *testing.T) {
+		missing := filepath.Join(project, "templates", "missing.yml")
+		os.WriteFile(missing, []byte("spec:\n  include:\n    - local: /missing.yml\n"), 0644)
+
+		_, err := NewComponent(missing, DefaultOptions())
+		assert.ErrorContains(t, err, fmt.Sprintf("%s: spec:include /missing.yml: ", missing))
+
+		findings, err := Lint(missing)
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, RuleInvalidInclude, findings[0].Rule)
+		assert.Equal(t, 3, findings[0].Line)
+		assert.Equal(t, 7, findings[0].Column)
+	})
+
+	t.Run("Invalid include", func(t *testing.T) {
+		os.WriteFile(filepath.Join(project, "shared", "invalid.yml"), []byte("inputs:\n  stage:\n    options: a\n"), 0644)
+		invalid := filepath.Join(project, "templates", "invalid.yml")
+		os.WriteFile(invalid, []byte("spec:\n  include:\n    - local: shared/inputs.yml\n    - local: shared/invalid.yml\n"), 0644)
+
+		_, err := NewComponent(invalid, DefaultOptions())
+		assert.ErrorContains(t, err, filepath.Join(project, "shared", "invalid.yml")+":3: cannot unmarshal")
+
+		findings, err := Lint(invalid)
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, RuleInvalidInclude, findings[0].Rule)
+		assert.Equal(t, 4, findings[0].Line)
+		assert.Equal(t, 7, findings[0].Column)
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"regexp"
+	"strings"
+)
+
+// interpolationRegex matches interpolation blocks like $[[ inputs.name | expand_vars ]]
+var interpolationRegex = regexp.MustCompile(`\$\[\[\s*(.*?)\s*\]\]`)
+
+// inputReferenceRegex matches the input reference and the functions applied to it
+var inputReferenceRegex = regexp.MustCompile(`^inputs\.([A-Za-z0-9_-]+)\s*(?:\|\s*(.*))?$`)
+
+// Interpolation is a reference to an input within a component template
+type Interpolation struct {
+	// Expression is the whole interpolation block, eg. $[[ inputs.name | expand_vars ]]
+	Expression string
+	Input      string
+	// Functions applied to the input in the order they are applied, eg. truncate(0,8)
+	Functions []string
+	// Offset of the expression within the template
+	Offset int
+	Line   int
+	Column int
+}
+
+// specEnd returns the offset of the first document following the spec
+// document, or -1 if the template has only a single document
+func specEnd(b []byte) int {
+	content := false
+	offset := 0
+	for _, line := range strings.SplitAfter(string(b), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(line, "---") && (len(trimmed) == 3 || line[3] == ' ' || line[3] == '\t') {
+			// a separator before any content starts the spec document
+			if content {
+				return offset + len(line)
+			}
+		} else if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			content = true
+		}
+		offset += len(line)
+	}
+	return -1
+}
+
+// FindInterpolations returns all references to inputs in the documents
+// following the spec of a component template
+func FindInterpolations(b []byte) []Interpolation {
+	start := specEnd(b)
+	if start < 0 {
+		return nil
+	}
+
+	interpolations := []Interpolation{}
+	for _, m := range interpolationRegex.FindAllSubmatchIndex(b[start:], -1) {
+		i, ok := parseInterpolation(string(b[start+m[0]:start+m[1]]), string(b[start+m[2]:start+m[3]]))
+		if !ok {
+			// other contexts like $[[ component.name ]] are not inputs
+			continue
+		}
+
+		i.Offset = start + m[0]
+		lineStart := strings.LastIndex(string(b[:i.Offset]), "\n") + 1
+		i.Line = strings.Count(string(b[:i.Offset]), "\n") + 1
+		i.Column = i.Offset - lineStart + 1
+		interpolations = append(interpolations, i)
+	}
+
+	return interpolations
+}
+
+// parseInterpolation parses the content of an interpolation block, and
+// returns false if it doesn't reference an input
+func parseInterpolation(expression, content string) (Interpolation, bool) {
+	ref := inputReferenceRegex.FindStringSubmatch(content)
+	if ref == nil {
+		return Interpolation{}, false
+	}
+
+	i := Interpolation{Expression: expression, Input: ref[1]}
+	if ref[2] != "" {
+		for _, f := range strings.Split(ref[2], "|") {
+			i.Functions = append(i.Functions, strings.TrimSpace(f))
+		}
+	}
+	return i, true
+}
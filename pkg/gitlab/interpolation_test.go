@@ -0,0 +1,31 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindInterpolations(t *testing.T) {
+	t.Run("Functions", func(t *testing.T) {
+		input := `# comment
+---
+spec:
+  inputs:
+    name:
+      description: $[[ inputs.ignored ]]
+---
+"$[[ inputs.name ]]-job":
+  script: echo $[[inputs.name|expand_vars| truncate(0,8)]] $[[ component.version ]]
+`
+		expected := []Interpolation{
+			{Expression: "$[[ inputs.name ]]", Input: "name", Offset: 86, Line: 8, Column: 2},
+			{Expression: "$[[inputs.name|expand_vars| truncate(0,8)]]", Input: "name", Functions: []string{"expand_vars", "truncate(0,8)"}, Offset: 126, Line: 9, Column: 16},
+		}
+		assert.Equal(t, expected, FindInterpolations([]byte(input)))
+	})
+
+	t.Run("Single document", func(t *testing.T) {
+		assert.Nil(t, FindInterpolations([]byte("job:\n  script: $[[ inputs.name ]]\n")))
+	})
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// globalKeywords are top level keys in a CI configuration, which are not jobs
+var globalKeywords = map[string]bool{
+	"after_script":  true,
+	"before_script": true,
+	"cache":         true,
+	"default":       true,
+	"image":         true,
+	"include":       true,
+	"services":      true,
+	"stages":        true,
+	"variables":     true,
+	"workflow":      true,
+}
+
+type JobRule struct {
+	If      string `json:"if,omitempty" yaml:"if,omitempty"`
+	When    string `json:"when,omitempty" yaml:"when,omitempty"`
+	Changes any    `json:"changes,omitempty" yaml:"changes,omitempty"`
+	Exists  any    `json:"exists,omitempty" yaml:"exists,omitempty"`
+}
+
+type Job struct {
+	Name    string    `json:"name" yaml:"name"`
+	Stage   string    `json:"stage,omitempty" yaml:"stage,omitempty"`
+	Image   string    `json:"image,omitempty" yaml:"image,omitempty"`
+	Extends []string  `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Rules   []JobRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Needs   []string  `json:"needs,omitempty" yaml:"needs,omitempty"`
+}
+
+// jobDefinition is used to decode the fields of a job, which support multiple notations
+type jobDefinition struct {
+	Stage   string    `yaml:"stage"`
+	Image   yaml.Node `yaml:"image"`
+	Extends yaml.Node `yaml:"extends"`
+	Rules   []JobRule `yaml:"rules"`
+	Needs   yaml.Node `yaml:"needs"`
+}
+
+// NewJobs returns all jobs defined in a CI configuration document. Hidden jobs
+// and global keywords are skipped.
+func NewJobs(doc *yaml.Node) []Job {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+	if doc.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	jobs := []Job{}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		name := doc.Content[i].Value
+		if strings.HasPrefix(name, ".") || globalKeywords[name] || doc.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+
+		var def jobDefinition
+		if err := doc.Content[i+1].Decode(&def); err != nil {
+			// incomplete information is better than no information at all
+			jobs = append(jobs, Job{Name: name})
+			continue
+		}
+
+		job := Job{
+			Name:    name,
+			Stage:   def.Stage,
+			Image:   def.Image.Value,
+			Extends: stringList(&def.Extends, ""),
+			Rules:   def.Rules,
+			Needs:   stringList(&def.Needs, "job"),
+		}
+		// image can be a string or a mapping with the image name
+		if def.Image.Kind == yaml.MappingNode {
+			job.Image = mappingValue(&def.Image, "name")
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs
+}
+
+// stringList returns the values of a scalar or a sequence node. For mappings
+// within a sequence, the value of key is used.
+func stringList(node *yaml.Node, key string) []string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		values := []string{}
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				values = append(values, item.Value)
+			} else if item.Kind == yaml.MappingNode && key != "" {
+				if value := mappingValue(item, key); value != "" {
+					values = append(values, value)
+				}
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) string {
+	if value := lookup(node, key); value != nil {
+		return value.Value
+	}
+	return ""
+}
+
+// escape pipes, as they would end the table cell
+func escapeCell(input string) string {
+	return strings.ReplaceAll(input, "|", "\\|")
+}
+
+func codeList(values []string) string {
+	codes := make([]string, len(values))
+	for i, v := range values {
+		codes[i] = fmt.Sprintf("`%s`", escapeCell(v))
+	}
+	return strings.Join(codes, ", ")
+}
+
+func (rule JobRule) Markdown() string {
+	var parts []string
+	if rule.If != "" {
+		parts = append(parts, fmt.Sprintf("`%s`", escapeCell(rule.If)))
+	}
+	if rule.Changes != nil {
+		parts = append(parts, "changes")
+	}
+	if rule.Exists != nil {
+		parts = append(parts, "exists")
+	}
+	if rule.When != "" {
+		parts = append(parts, fmt.Sprintf("_%s_", rule.When))
+	}
+	if len(parts) == 0 {
+		return "_always_"
+	}
+	return strings.Join(parts, " ")
+}
+
+func JobsMarkdownTable(jobs []Job) string {
+	var hasImage, hasExtends, hasNeeds, hasRules bool
+	for _, job := range jobs {
+		hasImage = hasImage || job.Image != ""
+		hasExtends = hasExtends || len(job.Extends) > 0
+		hasNeeds = hasNeeds || len(job.Needs) > 0
+		hasRules = hasRules || len(job.Rules) > 0
+	}
+
+	var sb strings.Builder
+	var divider strings.Builder
+
+	// Generate header
+	sb.WriteString("| Job | Stage |")
+	divider.WriteString("| --- | ----- |")
+	if hasImage {
+		sb.WriteString(" Image |")
+		divider.WriteString(" ----- |")
+	}
+	if hasExtends {
+		sb.WriteString(" Extends |")
+		divider.WriteString(" ------- |")
+	}
+	if hasNeeds {
+		sb.WriteString(" Needs |")
+		divider.WriteString(" ----- |")
+	}
+	if hasRules {
+		sb.WriteString(" Rules |")
+		divider.WriteString(" ----- |")
+	}
+	sb.WriteString("\n" + divider.String() + "\n")
+
+	for _, job := range jobs {
+		sb.WriteString(fmt.Sprintf("| `%s` | %s |", escapeCell(job.Name), escapeCell(job.Stage)))
+		if hasImage {
+			image := ""
+			if job.Image != "" {
+				image = fmt.Sprintf("`%s`", escapeCell(job.Image))
+			}
+			sb.WriteString(fmt.Sprintf(" %s |", image))
+		}
+		if hasExtends {
+			sb.WriteString(fmt.Sprintf(" %s |", codeList(job.Extends)))
+		}
+		if hasNeeds {
+			sb.WriteString(fmt.Sprintf(" %s |", codeList(job.Needs)))
+		}
+		if hasRules {
+			rules := make([]string, len(job.Rules))
+			for i, rule := range job.Rules {
+				rules[i] = rule.Markdown()
+			}
+			sb.WriteString(fmt.Sprintf(" %s |", strings.Join(rules, "<br>")))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
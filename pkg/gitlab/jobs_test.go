@@ -0,0 +1,71 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_NewJobs(t *testing.T) {
+	input := `
+stages: [build, test]
+variables:
+  FOO: bar
+.hidden:
+  stage: build
+build:
+  stage: build
+  image: golang:1.24
+  script: go build
+test:
+  extends: .hidden
+  image:
+    name: alpine
+    entrypoint: [""]
+  needs:
+    - build
+    - job: other
+      artifacts: false
+  rules:
+    - if: $CI_COMMIT_BRANCH
+      when: manual
+  script: go test`
+
+	var doc yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(input), &doc))
+
+	expected := []Job{
+		{Name: "build", Stage: "build", Image: "golang:1.24"},
+		{Name: "test", Image: "alpine", Extends: []string{".hidden"}, Needs: []string{"build", "other"}, Rules: []JobRule{{If: "$CI_COMMIT_BRANCH", When: "manual"}}},
+	}
+
+	assert.Equal(t, expected, NewJobs(&doc))
+}
+
+func Test_JobsMarkdownTable(t *testing.T) {
+	t.Run("Minimal", func(t *testing.T) {
+		jobs := []Job{{Name: "build", Stage: "build"}, {Name: "test"}}
+
+		expected := `| Job | Stage |
+| --- | ----- |
+| ` + "`build`" + ` | build |
+| ` + "`test`" + ` |  |
+`
+		assert.Equal(t, expected, JobsMarkdownTable(jobs))
+	})
+
+	t.Run("All fields", func(t *testing.T) {
+		jobs := []Job{
+			{Name: "build", Stage: "build", Image: "golang"},
+			{Name: "test", Extends: []string{".base"}, Needs: []string{"build", "other"}, Rules: []JobRule{{If: "$A || $B", When: "manual"}, {Changes: []string{"go.mod"}}, {}}},
+		}
+
+		expected := "| Job | Stage | Image | Extends | Needs | Rules |\n" +
+			"| --- | ----- | ----- | ------- | ----- | ----- |\n" +
+			"| `build` | build | `golang` |  |  |  |\n" +
+			"| `test` |  |  | `.base` | `build`, `other` | `$A \\|\\| $B` _manual_<br>changes<br>_always_ |\n"
+
+		assert.Equal(t, expected, JobsMarkdownTable(jobs))
+	})
+}
@@ -0,0 +1,326 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rules reported by the linter
+const (
+	RuleYamlSyntax          = "yaml-syntax"
+	RuleUnknownType         = "unknown-type"
+	RuleUnknownKey          = "unknown-key"
+	RuleInvalidRegex        = "invalid-regex"
+	RuleInvalidKeyValue     = "invalid-key-value"
+	RuleDefaultNotInOptions = "default-not-in-options"
+	RuleDefaultNoRegexMatch = "default-regex-mismatch"
+	RuleDefaultWrongType    = "default-type-mismatch"
+	RuleUnusedInput         = "unused-input"
+	RuleUndeclaredInput     = "undeclared-input"
+	RuleInvalidInclude      = "invalid-include"
+)
+
+// inputTypes are the types supported by GitLab for inputs
+var inputTypes = []string{"string", "number", "boolean", "array"}
+
+// inputKeys are the keys supported by GitLab on an input
+var inputKeys = []string{"default", "description", "options", "regex", "type"}
+
+// inputKeyKinds are the kinds of values expected for the keys of an input, the
+// default can be of any kind
+var inputKeyKinds = map[string]string{
+	"description": "string",
+	"options":     "sequence",
+	"regex":       "string",
+	"type":        "string",
+}
+
+// yamlErrorLine extracts the line from errors returned by the yaml parser
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+type Finding struct {
+	File     string
+	Line     int
+	Column   int
+	Severity Severity
+	Rule     string
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s (%s)", f.File, f.Line, f.Column, f.Severity, f.Message, f.Rule)
+}
+
+// sortFindings sorts findings by their location
+func sortFindings(findings []Finding) {
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+}
+
+// HasErrors returns true if at least one of the findings is an error
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint validates the spec of the component template at path
+func Lint(path string) ([]Finding, error) {
+	project, template, err := splitProject(path)
+	if err != nil {
+		return nil, err
+	}
+	return project.Lint(template)
+}
+
+// LintTemplate validates the spec of a component template. The file is only used
+// to report the location of the findings.
+func LintTemplate(file string, b []byte) []Finding {
+	l := &linter{file: file}
+	return l.lint(b)
+}
+
+func (l *linter) lint(b []byte) []Finding {
+	var doc yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	if err := decoder.Decode(&doc); err != nil {
+		if err == io.EOF {
+			return l.findings
+		}
+		return append(l.findings, syntaxFinding(l.file, err))
+	}
+	// the jobs are not linted, but have to be valid yaml
+	for {
+		var job yaml.Node
+		if err := decoder.Decode(&job); err == io.EOF {
+			break
+		} else if err != nil {
+			l.findings = append(l.findings, syntaxFinding(l.file, err))
+			break
+		}
+	}
+
+	inputs := lookup(lookup(&doc, "spec"), "inputs")
+	if inputs != nil && inputs.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(inputs.Content); i += 2 {
+			l.lintInput(inputs.Content[i].Value, inputs.Content[i+1])
+		}
+	} else {
+		inputs = nil
+	}
+
+	l.lintInterpolations(b, inputs)
+
+	sortFindings(l.findings)
+	return l.findings
+}
+
+// syntaxFinding converts an error of the yaml parser into a finding
+func syntaxFinding(file string, err error) Finding {
+	f := Finding{File: file, Line: 1, Column: 1, Severity: SeverityError, Rule: RuleYamlSyntax, Message: err.Error()}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		f.Line, _ = strconv.Atoi(m[1])
+		f.Message = strings.TrimPrefix(err.Error(), m[0])
+	}
+	return f
+}
+
+type linter struct {
+	file     string
+	findings []Finding
+	// inherited are inputs declared in files included using spec:include
+	inherited map[string]bool
+}
+
+func (l *linter) report(node *yaml.Node, severity Severity, rule, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		File:     l.file,
+		Line:     node.Line,
+		Column:   node.Column,
+		Severity: severity,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintInput(name string, input *yaml.Node) {
+	// an input without any keys is a mandatory string input
+	if input.Kind != yaml.MappingNode {
+		return
+	}
+
+	invalid := map[string]bool{}
+	for i := 0; i+1 < len(input.Content); i += 2 {
+		key, value := input.Content[i], input.Content[i+1]
+		if !slices.Contains(inputKeys, key.Value) {
+			l.report(key, SeverityError, RuleUnknownKey, "input %s has unknown key %q, supported are %s", name, key.Value, strings.Join(inputKeys, ", "))
+			continue
+		}
+		if kind, ok := inputKeyKinds[key.Value]; ok && !hasKind(value, kind) {
+			l.report(value, SeverityError, RuleInvalidKeyValue, "%s of input %s must be a %s", key.Value, name, kind)
+			invalid[key.Value] = true
+		}
+	}
+
+	inputType := "string"
+	if node := lookup(input, "type"); invalid["type"] {
+		// without a valid type, the default can't be validated
+		inputType = ""
+	} else if node != nil {
+		if !slices.Contains(inputTypes, node.Value) {
+			l.report(node, SeverityError, RuleUnknownType, "input %s has unknown type %q, supported are %s", name, node.Value, strings.Join(inputTypes, ", "))
+			// without a known type, the default can't be validated
+			inputType = ""
+		} else {
+			inputType = node.Value
+		}
+	}
+
+	var re *regexp.Regexp
+	regex := lookup(input, "regex")
+	if node := regex; node != nil && !invalid["regex"] {
+		var err error
+		if re, err = CompileRegex(node.Value); err != nil {
+			l.report(node, SeverityError, RuleInvalidRegex, "input %s has an invalid regex: %s", name, err)
+		}
+	}
+
+	def := lookup(input, "default")
+	if def == nil {
+		return
+	}
+
+	if inputType != "" && !matchesType(def, inputType) {
+		l.report(def, SeverityError, RuleDefaultWrongType, "default of input %s is not of type %s", name, inputType)
+	}
+
+	if options := lookup(input, "options"); options != nil && options.Kind == yaml.SequenceNode && def.Kind == yaml.ScalarNode {
+		found := false
+		for _, option := range options.Content {
+			if option.Kind == yaml.ScalarNode && option.Value == def.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			l.report(def, SeverityError, RuleDefaultNotInOptions, "default %q of input %s is not one of the options", def.Value, name)
+		}
+	}
+
+	if re != nil && def.Kind == yaml.ScalarNode && !re.MatchString(def.Value) {
+		l.report(def, SeverityError, RuleDefaultNoRegexMatch, "default %q of input %s does not match the regex %s", def.Value, name, regex.Value)
+	}
+}
+
+// hasKind reports whether the node is of the given kind, either string or sequence
+func hasKind(node *yaml.Node, kind string) bool {
+	if kind == "sequence" {
+		return node.Kind == yaml.SequenceNode
+	}
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!str"
+}
+
+// lintInterpolations cross-checks the inputs used in the jobs with the declared inputs
+func (l *linter) lintInterpolations(b []byte, inputs *yaml.Node) {
+	used := map[string]bool{}
+	for _, i := range FindInterpolations(b) {
+		used[i.Input] = true
+		if lookup(inputs, i.Input) == nil && !l.inherited[i.Input] {
+			l.findings = append(l.findings, Finding{
+				File:     l.file,
+				Line:     i.Line,
+				Column:   i.Column,
+				Severity: SeverityError,
+				Rule:     RuleUndeclaredInput,
+				Message:  fmt.Sprintf("input %s is used but not declared in the spec", i.Input),
+			})
+		}
+	}
+
+	if inputs == nil {
+		return
+	}
+	for i := 0; i+1 < len(inputs.Content); i += 2 {
+		if key := inputs.Content[i]; !used[key.Value] {
+			l.report(key, SeverityWarning, RuleUnusedInput, "input %s is declared but never used", key.Value)
+		}
+	}
+}
+
+// CompileRegex compiles a regex in the format used by GitLab, which is enclosed in slashes
+func CompileRegex(regex string) (*regexp.Regexp, error) {
+	pattern, ok := RegexPattern(regex)
+	if !ok {
+		return nil, fmt.Errorf("regex %q must be enclosed in /", regex)
+	}
+	return regexp.Compile(pattern)
+}
+
+// RegexPattern returns the pattern of a regex in the format used by GitLab,
+// and whether it was enclosed in slashes
+func RegexPattern(regex string) (string, bool) {
+	if len(regex) < 2 || !strings.HasPrefix(regex, "/") || !strings.HasSuffix(regex, "/") {
+		return regex, false
+	}
+	return regex[1 : len(regex)-1], true
+}
+
+func matchesType(node *yaml.Node, inputType string) bool {
+	switch inputType {
+	case "string":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str"
+	case "number":
+		return node.Kind == yaml.ScalarNode && (node.ShortTag() == "!!int" || node.ShortTag() == "!!float")
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!bool"
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	}
+	return false
+}
+
+// lookup returns the value of key in a mapping node, or nil if it doesn't exist
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LintTemplate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		input := `spec:
+  inputs:
+    job-prefix:
+      description: "Define a prefix for the job name"
+    job-stage:
+    environment:
+      options: ['test', 'staging', 'production']
+      default: test
+    concurrency:
+      type: number
+      default: 1
+    version:
+      type: string
+      regex: /^v\d\.\d+(\.\d+)$/
+      default: v1.0.0
+    export_results:
+      type: boolean
+      default: true
+    paths:
+      type: array
+      default: [a, b]
+---
+"$[[ inputs.job-prefix ]]-job":
+  stage: $[[ inputs.job-stage ]]
+  parallel: $[[ inputs.concurrency ]]
+  script:
+    - deploy $[[ inputs.environment ]] $[[ inputs.version | expand_vars ]]
+    - echo $[[ inputs.export_results ]]
+  artifacts:
+    paths: $[[ inputs.paths ]]`
+
+		assert.Empty(t, LintTemplate("template.yml", []byte(input)))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		input := `spec:
+  inputs:
+    unknown-type:
+      type: strin
+    not-in-options:
+      options: [a, b]
+      default: c
+    regex-mismatch:
+      regex: /^[ab]$/
+      default: c
+    invalid-regex:
+      regex: /(/
+    missing-slashes:
+      regex: ^a$
+    unknown-key:
+      descripton: typo
+    number:
+      type: number
+      default: "1"
+    boolean:
+      type: boolean
+      default: "yes"
+    array:
+      type: array
+      default: a
+    string:
+      type: string
+      default: [a]
+---
+job:
+  script: echo $[[ inputs.unknown-type ]] $[[ inputs.not-in-options ]] $[[ inputs.regex-mismatch ]] $[[ inputs.invalid-regex ]] $[[ inputs.missing-slashes ]] $[[ inputs.unknown-key ]] $[[ inputs.number ]] $[[ inputs.boolean ]] $[[ inputs.array ]] $[[ inputs.string ]]`
+
+		expected := []Finding{
+			{File: "template.yml", Line: 4, Column: 13, Severity: SeverityError, Rule: RuleUnknownType, Message: `input unknown-type has unknown type "strin", supported are string, number, boolean, array`},
+			{File: "template.yml", Line: 7, Column: 16, Severity: SeverityError, Rule: RuleDefaultNotInOptions, Message: `default "c" of input not-in-options is not one of the options`},
+			{File: "template.yml", Line: 10, Column: 16, Severity: SeverityError, Rule: RuleDefaultNoRegexMatch, Message: `default "c" of input regex-mismatch does not match the regex /^[ab]$/`},
+			{File: "template.yml", Line: 12, Column: 14, Severity: SeverityError, Rule: RuleInvalidRegex, Message: "input invalid-regex has an invalid regex: error parsing regexp: missing closing ): `(`"},
+			{File: "template.yml", Line: 14, Column: 14, Severity: SeverityError, Rule: RuleInvalidRegex, Message: `input missing-slashes has an invalid regex: regex "^a$" must be enclosed in /`},
+			{File: "template.yml", Line: 16, Column: 7, Severity: SeverityError, Rule: RuleUnknownKey, Message: `input unknown-key has unknown key "descripton", supported are default, description, options, regex, type`},
+			{File: "template.yml", Line: 19, Column: 16, Severity: SeverityError, Rule: RuleDefaultWrongType, Message: "default of input number is not of type number"},
+			{File: "template.yml", Line: 22, Column: 16, Severity: SeverityError, Rule: RuleDefaultWrongType, Message: "default of input boolean is not of type boolean"},
+			{File: "template.yml", Line: 25, Column: 16, Severity: SeverityError, Rule: RuleDefaultWrongType, Message: "default of input array is not of type array"},
+			{File: "template.yml", Line: 28, Column: 16, Severity: SeverityError, Rule: RuleDefaultWrongType, Message: "default of input string is not of type string"},
+		}
+
+		findings := LintTemplate("template.yml", []byte(input))
+		assert.Equal(t, expected, findings)
+		assert.True(t, HasErrors(findings))
+	})
+
+	t.Run("Invalid key values", func(t *testing.T) {
+		input := `spec:
+  inputs:
+    options:
+      options: a
+      default: a
+    description:
+      description: 3
+    regex:
+      regex: [a]
+      default: b
+    type:
+      type: {name: string}
+      default: [a]
+    valid:
+      description: "3"
+      options: [a]
+      default: a
+---
+job:
+  script: echo $[[ inputs.options ]] $[[ inputs.description ]] $[[ inputs.regex ]] $[[ inputs.type ]] $[[ inputs.valid ]]`
+
+		expected := []Finding{
+			{File: "template.yml", Line: 4, Column: 16, Severity: SeverityError, Rule: RuleInvalidKeyValue, Message: "options of input options must be a sequence"},
+			{File: "template.yml", Line: 7, Column: 20, Severity: SeverityError, Rule: RuleInvalidKeyValue, Message: "description of input description must be a string"},
+			{File: "template.yml", Line: 9, Column: 14, Severity: SeverityError, Rule: RuleInvalidKeyValue, Message: "regex of input regex must be a string"},
+			{File: "template.yml", Line: 12, Column: 13, Severity: SeverityError, Rule: RuleInvalidKeyValue, Message: "type of input type must be a string"},
+		}
+		assert.Equal(t, expected, LintTemplate("template.yml", []byte(input)))
+	})
+
+	t.Run("Syntax error", func(t *testing.T) {
+		input := "spec:\n  inputs:\n    a: b: c\n"
+
+		expected := []Finding{
+			{File: "template.yml", Line: 3, Column: 1, Severity: SeverityError, Rule: RuleYamlSyntax, Message: "mapping values are not allowed in this context"},
+		}
+		assert.Equal(t, expected, LintTemplate("template.yml", []byte(input)))
+	})
+
+	t.Run("Syntax error in jobs", func(t *testing.T) {
+		input := "spec:\n  inputs:\n---\nbuild:\n  script: [a,\n"
+
+		expected := []Finding{
+			{File: "template.yml", Line: 5, Column: 1, Severity: SeverityError, Rule: RuleYamlSyntax, Message: "did not find expected node content"},
+		}
+		assert.Equal(t, expected, LintTemplate("template.yml", []byte(input)))
+	})
+}
+
+func Test_LintTemplateInterpolations(t *testing.T) {
+	input := `spec:
+  inputs:
+    used:
+    unused:
+---
+job:
+  stage: $[[ inputs.used ]]
+  script: echo $[[ inputs.undeclared | truncate(0,8) ]] $[[ component.name ]]`
+
+	expected := []Finding{
+		{File: "template.yml", Line: 4, Column: 5, Severity: SeverityWarning, Rule: RuleUnusedInput, Message: "input unused is declared but never used"},
+		{File: "template.yml", Line: 8, Column: 16, Severity: SeverityError, Rule: RuleUndeclaredInput, Message: "input undeclared is used but not declared in the spec"},
+	}
+	assert.Equal(t, expected, LintTemplate("template.yml", []byte(input)))
+
+	t.Run("Without spec", func(t *testing.T) {
+		input := "job:\n  script: echo $[[ inputs.name ]]\n"
+		assert.Empty(t, LintTemplate("template.yml", []byte(input)))
+
+		input = "---\njob:\n  script: echo\n---\njob2:\n  script: echo $[[ inputs.name ]]\n"
+		expected := []Finding{
+			{File: "template.yml", Line: 6, Column: 16, Severity: SeverityError, Rule: RuleUndeclaredInput, Message: "input name is used but not declared in the spec"},
+		}
+		assert.Equal(t, expected, LintTemplate("template.yml", []byte(input)))
+	})
+}
+
+func Test_FindingString(t *testing.T) {
+	f := Finding{File: "templates/a.yml", Line: 3, Column: 5, Severity: SeverityWarning, Rule: "some-rule", Message: "something"}
+	assert.Equal(t, "templates/a.yml:3:5: warning: something (some-rule)", f.String())
+}
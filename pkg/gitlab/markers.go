@@ -0,0 +1,105 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// markerRegex matches markers like <!-- BEGIN COMPONENTS --> or <!-- END COMPONENT my-component -->
+var markerRegex = regexp.MustCompile(`<!--\s*(BEGIN|END)\s+(\S+(?:[ \t]+\S+)*?)\s*-->`)
+
+// RegionComponents is the name of the region containing all components
+const RegionComponents = "COMPONENTS"
+
+// ComponentRegion returns the name of the region containing a single component
+func ComponentRegion(name string) string {
+	return "COMPONENT " + name
+}
+
+// InjectRegions replaces the content between each pair of BEGIN/END markers in doc
+// with the content of the region with the same name. The markers are kept, so the
+// document can be updated again later on. Markers in fenced code blocks, eg.
+// documenting them, are ignored.
+func InjectRegions(doc string, regions map[string]string) (string, error) {
+	matches := findMarkers(doc)
+	if len(matches) == 0 {
+		known := make([]string, 0, len(regions))
+		for name := range regions {
+			known = append(known, fmt.Sprintf("<!-- BEGIN %s -->", name))
+		}
+		sort.Strings(known)
+		return "", fmt.Errorf("no markers found, expected at least one of %s", strings.Join(known, ", "))
+	}
+
+	var sb strings.Builder
+	var open string
+	var openLine int
+	last := 0
+
+	for _, m := range matches {
+		kind := doc[m[2]:m[3]]
+		name := doc[m[4]:m[5]]
+		line := strings.Count(doc[:m[0]], "\n") + 1
+
+		switch kind {
+		case "BEGIN":
+			if open != "" {
+				return "", fmt.Errorf("line %d: marker BEGIN %s found before END %s from line %d", line, name, open, openLine)
+			}
+			if _, ok := regions[name]; !ok {
+				return "", fmt.Errorf("line %d: unknown region %s", line, name)
+			}
+			// keep everything up to and including the BEGIN marker
+			sb.WriteString(doc[last:m[1]])
+			open = name
+			openLine = line
+		case "END":
+			if open == "" {
+				return "", fmt.Errorf("line %d: marker END %s without BEGIN", line, name)
+			}
+			if name != open {
+				return "", fmt.Errorf("line %d: marker END %s does not match BEGIN %s from line %d", line, name, open, openLine)
+			}
+			sb.WriteString("\n")
+			if content := strings.TrimSpace(regions[name]); content != "" {
+				sb.WriteString(content + "\n")
+			}
+			// the existing content between the markers is dropped
+			last = m[0]
+			open = ""
+		}
+	}
+
+	if open != "" {
+		return "", fmt.Errorf("line %d: marker BEGIN %s without END", openLine, open)
+	}
+
+	sb.WriteString(doc[last:])
+
+	return sb.String(), nil
+}
+
+// findMarkers returns the indexes of all markers in doc, which are not within a
+// fenced code block
+func findMarkers(doc string) [][]int {
+	var f fence
+	markers := [][]int{}
+	offset := 0
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		if !f.code(line) {
+			for _, m := range markerRegex.FindAllStringSubmatchIndex(line, -1) {
+				for i := range m {
+					m[i] += offset
+				}
+				markers = append(markers, m)
+			}
+		}
+		offset += len(line)
+	}
+	return markers
+}
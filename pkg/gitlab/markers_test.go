@@ -0,0 +1,94 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InjectRegions(t *testing.T) {
+	regions := map[string]string{
+		RegionComponents:           "## a\n\n## b\n",
+		ComponentRegion("special"): "## special\n",
+	}
+
+	t.Run("Replace content", func(t *testing.T) {
+		doc := `# Hand written
+
+<!-- BEGIN COMPONENTS -->
+outdated
+<!-- END COMPONENTS -->
+
+Some more text
+`
+		expected := `# Hand written
+
+<!-- BEGIN COMPONENTS -->
+## a
+
+## b
+<!-- END COMPONENTS -->
+
+Some more text
+`
+		result, err := InjectRegions(doc, regions)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+
+		// injecting again must not change anything
+		again, err := InjectRegions(result, regions)
+		assert.NoError(t, err)
+		assert.Equal(t, result, again)
+	})
+
+	t.Run("Markers in code blocks", func(t *testing.T) {
+		doc := "## Markers\n\n```markdown\n<!-- BEGIN COMPONENT my-comp -->\n<!-- END COMPONENT my-comp -->\n```\n\n~~~\n<!-- BEGIN COMPONENT special -->\n~~~\n\n<!-- BEGIN COMPONENTS -->\n<!-- END COMPONENTS -->\n"
+		expected := "## Markers\n\n```markdown\n<!-- BEGIN COMPONENT my-comp -->\n<!-- END COMPONENT my-comp -->\n```\n\n~~~\n<!-- BEGIN COMPONENT special -->\n~~~\n\n<!-- BEGIN COMPONENTS -->\n## a\n\n## b\n<!-- END COMPONENTS -->\n"
+
+		result, err := InjectRegions(doc, regions)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+
+		_, err = InjectRegions("```\n<!-- BEGIN COMPONENTS -->\n```\n", regions)
+		assert.ErrorContains(t, err, "no markers found")
+	})
+
+	t.Run("Multiple regions", func(t *testing.T) {
+		doc := "<!-- BEGIN COMPONENT special --><!-- END COMPONENT special -->\ntext\n<!--BEGIN COMPONENTS-->\n<!--END COMPONENTS-->\n"
+		expected := "<!-- BEGIN COMPONENT special -->\n## special\n<!-- END COMPONENT special -->\ntext\n<!--BEGIN COMPONENTS-->\n## a\n\n## b\n<!--END COMPONENTS-->\n"
+
+		result, err := InjectRegions(doc, regions)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Missing markers", func(t *testing.T) {
+		_, err := InjectRegions("# Hand written\n", regions)
+		assert.EqualError(t, err, "no markers found, expected at least one of <!-- BEGIN COMPONENT special -->, <!-- BEGIN COMPONENTS -->")
+	})
+
+	t.Run("Missing END", func(t *testing.T) {
+		_, err := InjectRegions("text\n<!-- BEGIN COMPONENTS -->\n", regions)
+		assert.EqualError(t, err, "line 2: marker BEGIN COMPONENTS without END")
+	})
+
+	t.Run("Missing BEGIN", func(t *testing.T) {
+		_, err := InjectRegions("<!-- END COMPONENTS -->\n", regions)
+		assert.EqualError(t, err, "line 1: marker END COMPONENTS without BEGIN")
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		_, err := InjectRegions("<!-- BEGIN COMPONENTS -->\n<!-- BEGIN COMPONENT special -->\n", regions)
+		assert.EqualError(t, err, "line 2: marker BEGIN COMPONENT special found before END COMPONENTS from line 1")
+	})
+
+	t.Run("Mismatched END", func(t *testing.T) {
+		_, err := InjectRegions("<!-- BEGIN COMPONENTS -->\n<!-- END COMPONENT special -->\n", regions)
+		assert.EqualError(t, err, "line 2: marker END COMPONENT special does not match BEGIN COMPONENTS from line 1")
+	})
+
+	t.Run("Unknown region", func(t *testing.T) {
+		_, err := InjectRegions("<!-- BEGIN COMPONENT missing -->\n<!-- END COMPONENT missing -->\n", regions)
+		assert.EqualError(t, err, "line 1: unknown region COMPONENT missing")
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// SortByPath keeps the order the templates are found in within templates/
+	SortByPath = "path"
+	// SortByName sorts the components alphabetically by name
+	SortByName = "name"
+)
+
+// SortComponents sorts the components in place. Components listed in
+// opts.Order come first, in the given order, followed by all other components
+// sorted by opts.Sort.
+func SortComponents(components []*Component, opts Options) error {
+	var less func(a, b *Component) bool
+	switch opts.Sort {
+	case SortByPath, "":
+		// the components are already found in lexical order
+		less = func(a, b *Component) bool { return false }
+	case SortByName:
+		less = func(a, b *Component) bool {
+			if !strings.EqualFold(a.Name, b.Name) {
+				return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+			}
+			return a.Path < b.Path
+		}
+	default:
+		return fmt.Errorf("unsupported sort %q, supported are %s and %s", opts.Sort, SortByPath, SortByName)
+	}
+
+	position := map[string]int{}
+	for i, name := range opts.Order {
+		if _, ok := position[name]; !ok {
+			position[name] = i
+		}
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		pi, iOrdered := position[components[i].Name]
+		pj, jOrdered := position[components[j].Name]
+		switch {
+		case iOrdered && jOrdered:
+			return pi < pj
+		case iOrdered != jOrdered:
+			return iOrdered
+		}
+		return less(components[i], components[j])
+	})
+	return nil
+}
+
+// ComponentGroup are the components of a category
+type ComponentGroup struct {
+	// Category is empty for components without category
+	Category   string
+	Components []*Component
+}
+
+// GroupComponents groups the components by category, keeping their order. The
+// categories are ordered by their first component, components without a
+// category are grouped last.
+func GroupComponents(components []*Component) []ComponentGroup {
+	groups := []ComponentGroup{}
+	index := map[string]int{}
+	var uncategorized []*Component
+	for _, c := range components {
+		if c.Category == "" {
+			uncategorized = append(uncategorized, c)
+			continue
+		}
+		i, ok := index[c.Category]
+		if !ok {
+			i = len(groups)
+			index[c.Category] = i
+			groups = append(groups, ComponentGroup{Category: c.Category})
+		}
+		groups[i].Components = append(groups[i].Components, c)
+	}
+	if len(uncategorized) > 0 {
+		groups = append(groups, ComponentGroup{Components: uncategorized})
+	}
+	return groups
+}
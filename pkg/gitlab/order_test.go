@@ -0,0 +1,69 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(components []*Component) []string {
+	names := make([]string, len(components))
+	for i, c := range components {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func Test_SortComponents(t *testing.T) {
+	components := func() []*Component {
+		return []*Component{
+			{Name: "deploy", Path: "templates/deploy/template.yml"},
+			{Name: "Build", Path: "templates/z/build.yml"},
+			{Name: "lint", Path: "templates/lint.yml"},
+			{Name: "audit", Path: "templates/x/audit.yml"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected []string
+	}{
+		{"Path", Options{Sort: SortByPath}, []string{"deploy", "Build", "lint", "audit"}},
+		{"Name", Options{Sort: SortByName}, []string{"audit", "Build", "deploy", "lint"}},
+		{"Order", Options{Sort: SortByName, Order: []string{"lint", "deploy", "missing"}}, []string{"lint", "deploy", "audit", "Build"}},
+		{"Order by path", Options{Order: []string{"audit"}}, []string{"audit", "deploy", "Build", "lint"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := components()
+			assert.NoError(t, SortComponents(c, tt.opts))
+			assert.Equal(t, tt.expected, names(c))
+		})
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		err := SortComponents(components(), Options{Sort: "size"})
+		assert.EqualError(t, err, `unsupported sort "size", supported are path and name`)
+	})
+}
+
+func Test_GroupComponents(t *testing.T) {
+	components := []*Component{
+		{Name: "build", Category: "Build"},
+		{Name: "lint"},
+		{Name: "deploy", Category: "Release"},
+		{Name: "test", Category: "Build"},
+	}
+
+	groups := GroupComponents(components)
+	assert.Len(t, groups, 3)
+	assert.Equal(t, "Build", groups[0].Category)
+	assert.Equal(t, []string{"build", "test"}, names(groups[0].Components))
+	assert.Equal(t, "Release", groups[1].Category)
+	assert.Equal(t, []string{"deploy"}, names(groups[1].Components))
+	assert.Equal(t, "", groups[2].Category)
+	assert.Equal(t, []string{"lint"}, names(groups[2].Components))
+
+	assert.Empty(t, GroupComponents(nil))
+}
@@ -0,0 +1,284 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Project is a component project read from a file system, eg. a directory or
+// a release archive
+type Project struct {
+	FS fs.FS
+	// Root is the location of the project. It is prepended to the paths of the
+	// components and findings, so they can be found by the user.
+	Root string
+}
+
+// NewProject returns the project within the given directory
+func NewProject(dir string) *Project {
+	return &Project{FS: os.DirFS(dir), Root: dir}
+}
+
+// path returns the location of a file of the project shown to the user
+func (p *Project) path(name string) string {
+	return filepath.Join(p.Root, filepath.FromSlash(name))
+}
+
+// ReadFile reads a file of the project. The name is relative to the project
+// root and may use the separator of the operating system.
+func (p *Project) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(p.FS, path.Clean(filepath.ToSlash(name)))
+}
+
+// FindComponents returns the paths of all component templates within templates/,
+// relative to the project root
+func (p *Project) FindComponents() ([]string, error) {
+	components := []string{}
+	if _, err := fs.Stat(p.FS, "templates"); errors.Is(err, fs.ErrNotExist) {
+		return components, nil
+	}
+
+	// find all yaml files in project
+	err := fs.WalkDir(p.FS, "templates", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// within the templates directory, we take all the yaml/yml files
+		if path.Dir(name) == "templates" && (path.Ext(name) == ".yaml" || path.Ext(name) == ".yml") {
+			components = append(components, name)
+		} else if path.Dir(name) != "templates" && isDirectoryTemplate(name) {
+			// if we are in a subdirectory, only the template.yaml/yml files are relevant
+			components = append(components, name)
+		}
+		return nil
+	})
+
+	return components, err
+}
+
+// FindComponent returns the path of the template of the component with the
+// given name, relative to the project root. It fails if the name is not unique,
+// eg. for templates/build.yml and templates/build/template.yml.
+func (p *Project) FindComponent(name string) (string, error) {
+	templates, err := p.FindComponents()
+	if err != nil {
+		return "", err
+	}
+
+	found := []string{}
+	for _, template := range templates {
+		if componentName(template) == name {
+			found = append(found, template)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("component %s not found", name)
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("component %s is ambiguous, found %s", name, strings.Join(found, ", "))
+}
+
+// componentName returns the name of the component of a template. It is the name
+// of its directory, or the name of the file without extension if the component
+// is a single file.
+func componentName(template string) string {
+	if isDirectoryTemplate(template) {
+		return path.Base(path.Dir(template))
+	}
+	return strings.TrimSuffix(path.Base(template), path.Ext(template))
+}
+
+// readComponentFile reads a file next to the component template, if it exists
+func (p *Project) readComponentFile(template, name string) ([]byte, error) {
+	if name == "" {
+		return nil, nil
+	}
+	file := path.Join(path.Dir(template), name)
+	b, err := p.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fileError(p.path(file), err)
+	}
+	return b, nil
+}
+
+// Component reads the component from the given template file, relative to the
+// project root. The header, footer and template of directory components are
+// read using the options. The header of a single file component is read from a
+// markdown file next to it, eg. templates/build.md for templates/build.yml.
+// Without header file, the comment block at the top of the template is used if
+// enabled by the options.
+func (p *Project) Component(template string, opts Options) (*Component, error) {
+	file := p.path(template)
+	b, err := p.ReadFile(template)
+	if err != nil {
+		return nil, fileError(file, err)
+	}
+
+	name := componentName(template)
+	opts = opts.For(name)
+
+	var headerFile string
+	var footer []byte
+	var tmpl []byte
+	// GitLab allows yaml files directly in template directory, their header is
+	// the markdown file with the same name
+	if isDirectoryTemplate(template) {
+		headerFile = opts.ComponentHeader
+
+		if footer, err = p.readComponentFile(template, opts.ComponentFooter); err != nil {
+			return nil, err
+		}
+		if tmpl, err = p.readComponentFile(template, opts.ComponentTemplate); err != nil {
+			return nil, err
+		}
+	} else {
+		headerFile = name + ".md"
+	}
+
+	header, err := p.readComponentFile(template, headerFile)
+	if err != nil {
+		return nil, err
+	}
+	headerPath := p.path(path.Join(path.Dir(template), headerFile))
+	if len(header) == 0 && opts.HeaderFromComment {
+		header = []byte(leadingComment(b))
+		headerPath = file
+	}
+	fm, header, err := splitFrontMatter(header)
+	if err != nil {
+		return nil, fileError(headerPath, err)
+	}
+
+	c := &Component{Name: name, Path: file, Header: string(header), Footer: string(footer), Template: string(tmpl), Category: fm.Category, Description: fm.Description}
+	if category := opts.overrides(name).Category; category != nil {
+		c.Category = *category
+	}
+
+	// the first document contains the spec, all following documents the jobs.
+	// A template without spec only contains jobs.
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	var first yaml.Node
+	if err := decoder.Decode(&first); err != nil && err != io.EOF {
+		return nil, fileError(file, err)
+	}
+	if lookup(&first, "spec") != nil {
+		var header struct {
+			Spec *ComponentSpec `yaml:"spec"`
+		}
+		if err := first.Decode(&header); err != nil {
+			return nil, fileError(file, err)
+		}
+		c.Spec = header.Spec
+	} else {
+		c.Jobs = append(c.Jobs, NewJobs(&first)...)
+	}
+	if c.Spec != nil {
+		if err := c.Spec.resolveIncludes(p.FS, p.Root); err != nil {
+			// errors within the included file already carry its path
+			var fileErr *FileError
+			if errors.As(err, &fileErr) {
+				return nil, err
+			}
+			return nil, &FileError{File: file, Err: err}
+		}
+	}
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fileError(file, err)
+		}
+		c.Jobs = append(c.Jobs, NewJobs(&doc)...)
+	}
+
+	return c, nil
+}
+
+// Components loads all components within the project and sorts them. With
+// keepGoing, the components which could be loaded are returned together with
+// the errors of all others.
+func (p *Project) Components(opts Options, keepGoing bool) ([]*Component, error) {
+	paths, err := p.FindComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]*Component, 0, len(paths))
+	errs := []error{}
+	for _, path := range paths {
+		c, err := p.Component(path, opts)
+		if err != nil {
+			if !keepGoing {
+				return nil, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		components = append(components, c)
+	}
+	if err := SortComponents(components, opts); err != nil {
+		return nil, err
+	}
+	return components, errors.Join(errs...)
+}
+
+// Lint validates the spec of the component template, relative to the project root
+func (p *Project) Lint(template string) ([]Finding, error) {
+	file := p.path(template)
+	b, err := p.ReadFile(template)
+	if err != nil {
+		return nil, fileError(file, err)
+	}
+	l := &linter{file: file, inherited: map[string]bool{}}
+
+	// inputs included using spec:include are declared as well
+	var first yaml.Node
+	if yaml.NewDecoder(bytes.NewReader(b)).Decode(&first) == nil {
+		if include := lookup(lookup(&first, "spec"), "include"); include != nil && include.Kind == yaml.SequenceNode {
+			for _, node := range include.Content {
+				spec := &ComponentSpec{Include: make([]SpecInclude, 1)}
+				if node.Decode(&spec.Include[0]) != nil {
+					continue
+				}
+				// each include is reported at its own position
+				if err := spec.resolveIncludes(p.FS, p.Root); err != nil {
+					l.report(node, SeverityError, RuleInvalidInclude, "%s", err)
+				}
+				for name := range spec.Inputs {
+					l.inherited[name] = true
+				}
+			}
+		}
+	}
+
+	return l.lint(b), nil
+}
+
+// splitProject splits the path of a component template on disk into the
+// project and the path of the template within the project
+func splitProject(file string) (*Project, string, error) {
+	root := projectRoot(file)
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return nil, "", err
+	}
+	return NewProject(root), filepath.ToSlash(rel), nil
+}
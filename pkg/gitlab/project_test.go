@@ -0,0 +1,173 @@
+package gitlab
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Project(t *testing.T) {
+	project := &Project{Root: "release.tar.gz", FS: fstest.MapFS{
+		"templates/build/template.yml": {Data: []byte(`spec:
+  include:
+    - local: /shared/inputs.yml
+  inputs:
+    image:
+      default: alpine
+---
+build:
+  stage: $[[ inputs.stage ]]
+  image: $[[ inputs.image ]]
+`)},
+		"templates/build/HEADER.md": {Data: []byte("---\ncategory: Build\n---\nBuilds the project\n")},
+		"templates/lint.yml":        {Data: []byte("spec:\n  inputs:\n    stage:\n      type: strin\n")},
+		"templates/README.md":       {Data: []byte("not a component\n")},
+		"shared/inputs.yml":         {Data: []byte("inputs:\n  stage:\n    default: build\n")},
+	}}
+
+	t.Run("FindComponents", func(t *testing.T) {
+		components, err := project.FindComponents()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"templates/build/template.yml", "templates/lint.yml"}, components)
+	})
+
+	t.Run("FindComponent", func(t *testing.T) {
+		template, err := project.FindComponent("build")
+		assert.NoError(t, err)
+		assert.Equal(t, "templates/build/template.yml", template)
+
+		template, err = project.FindComponent("lint")
+		assert.NoError(t, err)
+		assert.Equal(t, "templates/lint.yml", template)
+
+		_, err = project.FindComponent("deploy")
+		assert.EqualError(t, err, "component deploy not found")
+
+		duplicated := &Project{FS: fstest.MapFS{
+			"templates/build.yml":          {Data: []byte("spec:\n")},
+			"templates/build/template.yml": {Data: []byte("spec:\n")},
+		}}
+		_, err = duplicated.FindComponent("build")
+		assert.EqualError(t, err, "component build is ambiguous, found templates/build/template.yml, templates/build.yml")
+	})
+
+	t.Run("Component", func(t *testing.T) {
+		c, err := project.Component("templates/build/template.yml", DefaultOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, "build", c.Name)
+		assert.Equal(t, "release.tar.gz/templates/build/template.yml", c.Path)
+		assert.Equal(t, "Builds the project\n", c.Header)
+		assert.Equal(t, "Build", c.Category)
+		assert.Equal(t, "build", c.Spec.Inputs["stage"].Default.String())
+		assert.Equal(t, "shared/inputs.yml", c.Spec.Inputs["stage"].InheritedFrom)
+		assert.Len(t, c.Jobs, 1)
+	})
+
+	t.Run("Category override", func(t *testing.T) {
+		category := "Compile"
+		opts := DefaultOptions()
+		opts.Components = map[string]ComponentOverrides{"build": {Category: &category}, "lint": {Category: &category}}
+
+		c, err := project.Component("templates/build/template.yml", opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "Compile", c.Category)
+
+		c, err = project.Component("templates/lint.yml", opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "Compile", c.Category)
+	})
+
+	t.Run("Missing component", func(t *testing.T) {
+		_, err := project.Component("templates/missing.yml", DefaultOptions())
+		assert.ErrorContains(t, err, "release.tar.gz/templates/missing.yml: ")
+	})
+
+	t.Run("Lint", func(t *testing.T) {
+		findings, err := project.Lint("templates/lint.yml")
+		assert.NoError(t, err)
+		assert.Len(t, findings, 2)
+		assert.Equal(t, "release.tar.gz/templates/lint.yml", findings[1].File)
+		assert.Equal(t, RuleUnknownType, findings[1].Rule)
+	})
+
+	t.Run("Broken component", func(t *testing.T) {
+		broken := &Project{Root: "project", FS: fstest.MapFS{
+			"templates/types.yml": {Data: []byte("spec:\n  inputs:\n    stage:\n      description: [a]\n")},
+			"templates/jobs.yml":  {Data: []byte("spec:\n  inputs:\n---\nbuild:\n  script: [a,\n")},
+		}}
+
+		_, err := broken.Component("templates/types.yml", DefaultOptions())
+		assert.EqualError(t, err, "project/templates/types.yml:4: cannot unmarshal !!seq into string")
+
+		_, err = broken.Component("templates/jobs.yml", DefaultOptions())
+		assert.EqualError(t, err, "project/templates/jobs.yml:5: did not find expected node content")
+	})
+
+	t.Run("Description of single file components", func(t *testing.T) {
+		described := &Project{FS: fstest.MapFS{
+			"templates/sidecar.yml":      {Data: []byte("# Ignored, as there is a sidecar file\nspec:\n  inputs:\n")},
+			"templates/sidecar.md":       {Data: []byte("---\ncategory: Docs\n---\nDocumented in a sidecar file.\n")},
+			"templates/comment.yml":      {Data: []byte("# Documented in a comment.\n#\n# Second paragraph\nspec:\n  inputs:\n")},
+			"templates/plain.yml":        {Data: []byte("spec:\n  inputs:\n")},
+			"templates/dir/template.yml": {Data: []byte("# Documented in a comment\nspec:\n  inputs:\n")},
+		}}
+
+		c, err := described.Component("templates/comment.yml", DefaultOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, "", c.Header)
+
+		opts := DefaultOptions()
+		opts.HeaderFromComment = true
+
+		c, err = described.Component("templates/sidecar.yml", opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "Documented in a sidecar file.\n", c.Header)
+		assert.Equal(t, "Docs", c.Category)
+
+		c, err = described.Component("templates/comment.yml", opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "Documented in a comment.\n\nSecond paragraph\n", c.Header)
+		assert.Equal(t, "Documented in a comment.", c.Summary())
+
+		c, err = described.Component("templates/plain.yml", opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "", c.Header)
+
+		c, err = described.Component("templates/dir/template.yml", opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "Documented in a comment\n", c.Header)
+	})
+
+	t.Run("Without spec", func(t *testing.T) {
+		specless := &Project{FS: fstest.MapFS{
+			"templates/jobs.yml":     {Data: []byte("build:\n  stage: build\n  script: make\n")},
+			"templates/keywords.yml": {Data: []byte("header:\n  stage: test\nfooter:\n  stage: test\nname:\n  stage: test\npath:\n  stage: test\n")},
+		}}
+
+		c, err := specless.Component("templates/jobs.yml", DefaultOptions())
+		assert.NoError(t, err)
+		assert.Nil(t, c.Spec)
+		assert.Equal(t, []Job{{Name: "build", Stage: "build"}}, c.Jobs)
+
+		c, err = specless.Component("templates/keywords.yml", DefaultOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, "keywords", c.Name)
+		assert.Equal(t, "", c.Header)
+		assert.Equal(t, []string{"header", "footer", "name", "path"}, jobNames(c.Jobs))
+	})
+
+	t.Run("No templates", func(t *testing.T) {
+		components, err := (&Project{FS: fstest.MapFS{}}).FindComponents()
+		assert.NoError(t, err)
+		assert.Empty(t, components)
+	})
+}
+
+func jobNames(jobs []Job) []string {
+	names := make([]string, len(jobs))
+	for i, j := range jobs {
+		names[i] = j.Name
+	}
+	return names
+}
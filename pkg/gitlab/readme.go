@@ -0,0 +1,333 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// defaultHeader is used if the project has no header file. It is followed by
+// the table of contents.
+const defaultHeader = "# GitLab CI Components\n\nThis repository contains the following components:\n\n"
+
+// otherComponents is the heading of the components without category, if other
+// components have one
+const otherComponents = "Other components"
+
+// ReadmeOptions configure the README of a project
+type ReadmeOptions struct {
+	Options
+	// Output is the path the README is written to, the paths of the component
+	// READMEs and their links are derived from it. Defaults to README.md within
+	// the project root.
+	Output string
+	// Header is the file prepended to the components, relative to the project.
+	// Without header file, a default header is used.
+	Header string
+	// Footer is the file appended to the components, relative to the project
+	Footer string
+	// TOC generates the table of contents in the default header instead of
+	// using the [[_TOC_]] macro of GitLab
+	TOC bool
+	// Summary renders a table summarizing all components before the components
+	Summary bool
+	// ComponentReadme is the name of the README written into the directory of
+	// each directory component, which is only linked from the README. Single
+	// file components are always rendered into the README.
+	ComponentReadme string
+	// Inject only replaces the content between markers in Existing, the
+	// header and footer are not used
+	Inject bool
+	// Existing is the current content of the output file
+	Existing string
+	// KeepGoing reports the errors of all components together instead of
+	// stopping at the first one
+	KeepGoing bool
+}
+
+// DefaultReadmeOptions returns the options used by the CLI, if no flags are given
+func DefaultReadmeOptions() ReadmeOptions {
+	return ReadmeOptions{
+		Options: DefaultOptions(),
+		Header:  "HEADER.md",
+		Footer:  "FOOTER.md",
+	}
+}
+
+// File is a file rendered for the project
+type File struct {
+	Path    string
+	Content string
+}
+
+// Readme renders the README of the project. The README is the first file,
+// followed by the READMEs of the components.
+func (p *Project) Readme(opts ReadmeOptions) ([]File, error) {
+	if opts.Output == "" {
+		opts.Output = p.path("README.md")
+	}
+
+	tmpl, err := p.template(opts.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	// errors of single components are collected with KeepGoing
+	components, err := p.Components(opts.Options, opts.KeepGoing)
+	if err != nil && components == nil {
+		return nil, err
+	}
+
+	r := &readme{project: p, opts: opts, tmpl: tmpl, regions: map[string]string{}, errs: []error{err}}
+	all, toc, err := r.sections(components)
+	if err != nil {
+		return nil, err
+	}
+	if err := errors.Join(r.errs...); err != nil {
+		return nil, err
+	}
+	r.regions[RegionComponents] = all
+
+	content, err := r.main(all, components, toc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]File{{Path: opts.Output, Content: content}}, r.files...), nil
+}
+
+// template returns the template at the given path within the project, or the
+// default template if the path is empty
+func (p *Project) template(name string) (*template.Template, error) {
+	if name == "" {
+		return NewTemplate("default", DefaultTemplate)
+	}
+
+	b, err := p.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewTemplate(name, string(b))
+}
+
+// readme collects the parts of the README of a project while its components
+// are rendered
+type readme struct {
+	project *Project
+	opts    ReadmeOptions
+	tmpl    *template.Template
+	// files are the READMEs of the components
+	files []File
+	// regions are the contents of the markers used with Inject
+	regions map[string]string
+	errs    []error
+}
+
+// render returns the markdown of the component, or the link to the README
+// within the directory of the component
+func (r *readme) render(c *Component, opts Options) (md string, link string, err error) {
+	tmpl := r.tmpl
+	if c.Template != "" {
+		tmpl, err = NewTemplate(c.Name, c.Template)
+		if err != nil {
+			return "", "", err
+		}
+	} else if path := opts.For(c.Name).Template; path != opts.Template {
+		// the config file overrides the template for this component
+		tmpl, err = r.project.template(path)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if r.opts.ComponentReadme != "" && c.IsDirectory() {
+		readme, err := c.Readme(tmpl, opts, r.opts.ComponentReadme, r.opts.Output)
+		if err != nil {
+			return "", "", err
+		}
+		r.files = append(r.files, File{Path: readme.Path, Content: readme.Content})
+
+		// the README only links to the component README
+		return "", readme.Link, nil
+	}
+
+	md, err = c.Render(tmpl, opts)
+	return md, "", err
+}
+
+// sections renders all components, grouped below a heading per category if
+// there are any. The entries of the table of contents point to the offset of
+// their section.
+func (r *readme) sections(components []*Component) (string, []TOCEntry, error) {
+	grouped := false
+	for _, c := range components {
+		grouped = grouped || c.Category != ""
+	}
+
+	var sb strings.Builder
+	toc := []TOCEntry{}
+	for _, group := range GroupComponents(components) {
+		opts := r.opts.Options
+		var groupEntry *TOCEntry
+		if grouped {
+			title := group.Category
+			if title == "" {
+				title = otherComponents
+			}
+			toc = append(toc, TOCEntry{Title: title, Offset: sb.Len()})
+			groupEntry = &toc[len(toc)-1]
+			fmt.Fprintf(&sb, "%s %s\n\n", strings.Repeat("#", opts.HeaderLevel), title)
+			opts.HeaderLevel++
+		}
+
+		var index strings.Builder
+		var sections strings.Builder
+		entries := []TOCEntry{}
+		for _, c := range group.Components {
+			md, link, err := r.render(c, opts)
+			if err != nil {
+				if !r.opts.KeepGoing {
+					return "", nil, err
+				}
+				r.errs = append(r.errs, err)
+				continue
+			}
+			// the offset within the sections is moved below the index afterwards
+			entry := TOCEntry{Title: c.Name, Link: link, Offset: sections.Len()}
+			entries = append(entries, entry)
+			if link != "" {
+				md = ReadmeIndex([]TOCEntry{entry})
+				index.WriteString(md)
+			} else {
+				sections.WriteString(md)
+			}
+			r.regions[ComponentRegion(c.Name)] = md
+		}
+
+		if index.Len() > 0 {
+			sb.WriteString(index.String() + "\n")
+		}
+		for i := range entries {
+			entries[i].Offset += sb.Len()
+		}
+		sb.WriteString(sections.String())
+
+		if groupEntry != nil {
+			groupEntry.Children = entries
+		} else {
+			toc = append(toc, entries...)
+		}
+	}
+	return sb.String(), toc, nil
+}
+
+// main renders the README of the project around the sections of all components
+func (r *readme) main(all string, components []*Component, toc []TOCEntry) (string, error) {
+	if r.opts.Inject {
+		// the headings of the existing file are unknown, only the components are considered
+		toc = AnchorEntries(toc, Headings(all), 0)
+		r.regions[RegionTOC] = TableOfContents(toc)
+		r.regions[RegionSummary] = SummaryTable(components, Links(toc))
+
+		readme, err := InjectRegions(r.opts.Existing, r.regions)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", r.opts.Output, err)
+		}
+		return readme, nil
+	}
+
+	header := defaultHeader + "[[_TOC_]]\n"
+	if r.opts.TOC {
+		header = defaultHeader + TOCPlaceholder + "\n"
+	}
+	if b, err := r.readFile(r.opts.Header); err != nil {
+		return "", err
+	} else if b != nil {
+		header = string(b)
+	}
+
+	// the anchors of the components depend on all headings before them, the
+	// ones of the header as well as the ones rendered by the components
+	prefix := strings.ReplaceAll(header, TOCPlaceholder, "") + "\n"
+	toc = AnchorEntries(toc, Headings(prefix+all), len(prefix))
+	header = strings.ReplaceAll(header, TOCPlaceholder, strings.TrimSuffix(TableOfContents(toc), "\n"))
+
+	var sb strings.Builder
+	sb.WriteString(header)
+
+	sb.WriteString("\n")
+	if r.opts.Summary {
+		sb.WriteString(SummaryTable(components, Links(toc)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(all)
+
+	footer, err := r.readFile(r.opts.Footer)
+	if err != nil {
+		return "", err
+	}
+	sb.Write(footer)
+
+	sb.WriteString("\n")
+
+	return strings.TrimSpace(sb.String()) + "\n", nil
+}
+
+// readFile reads a file of the project, if it exists
+func (r *readme) readFile(name string) ([]byte, error) {
+	if name == "" {
+		return nil, nil
+	}
+	b, err := r.project.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// ComponentReadme is a README written into the directory of a component, which
+// is linked from the README of the project
+type ComponentReadme struct {
+	Path    string
+	Content string
+	// Link is the path of the README relative to the README of the project
+	Link string
+}
+
+// Readme renders the README named file within the directory of the component.
+// The README is a standalone document, so the component is the top level
+// heading. The link is relative to the directory of output.
+func (c *Component) Readme(tmpl *template.Template, opts Options, file, output string) (ComponentReadme, error) {
+	opts.HeaderLevel = 1
+	md, err := c.Render(tmpl, opts)
+	if err != nil {
+		return ComponentReadme{}, err
+	}
+
+	path := filepath.Join(filepath.Dir(c.Path), file)
+	link, err := filepath.Rel(filepath.Dir(output), path)
+	if err != nil {
+		return ComponentReadme{}, err
+	}
+
+	return ComponentReadme{
+		Path:    path,
+		Content: strings.TrimSpace(md) + "\n",
+		Link:    filepath.ToSlash(link),
+	}, nil
+}
+
+// ReadmeIndex renders a list linking the entries, eg. to the READMEs of the
+// components
+func ReadmeIndex(entries []TOCEntry) string {
+	var sb strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "- [%s](%s)\n", e.Title, e.Link)
+	}
+	return sb.String()
+}
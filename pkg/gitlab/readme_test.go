@@ -0,0 +1,158 @@
+package gitlab
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Readme(t *testing.T) {
+	c := &Component{Name: "build", Path: "project/templates/build/template.yml", Header: "Builds the project.\n"}
+	tmpl, err := NewTemplate("default", DefaultTemplate)
+	assert.NoError(t, err)
+
+	t.Run("Next to the output", func(t *testing.T) {
+		readme, err := c.Readme(tmpl, DefaultOptions(), "README.md", "project/README.md")
+		assert.NoError(t, err)
+		assert.Equal(t, "project/templates/build/README.md", readme.Path)
+		assert.Equal(t, "templates/build/README.md", readme.Link)
+		assert.Equal(t, "# build\n\nBuilds the project.\n", readme.Content)
+	})
+
+	t.Run("Output in another directory", func(t *testing.T) {
+		readme, err := c.Readme(tmpl, DefaultOptions(), "index.md", "project/docs/README.md")
+		assert.NoError(t, err)
+		assert.Equal(t, "project/templates/build/index.md", readme.Path)
+		assert.Equal(t, "../templates/build/index.md", readme.Link)
+	})
+
+	t.Run("Absolute and relative paths", func(t *testing.T) {
+		_, err := c.Readme(tmpl, DefaultOptions(), "README.md", "/project/README.md")
+		assert.Error(t, err)
+	})
+}
+
+func Test_ReadmeIndex(t *testing.T) {
+	entries := []TOCEntry{
+		{Title: "build", Link: "templates/build/README.md"},
+		{Title: "deploy", Link: "../templates/deploy/README.md"},
+	}
+
+	expected := `- [build](templates/build/README.md)
+- [deploy](../templates/deploy/README.md)
+`
+	assert.Equal(t, expected, ReadmeIndex(entries))
+	assert.Equal(t, "", ReadmeIndex(nil))
+}
+
+func Test_ProjectReadme(t *testing.T) {
+	project := &Project{Root: "project", FS: fstest.MapFS{
+		"templates/build/template.yml": {Data: []byte("spec:\n  inputs:\n    image:\n      default: alpine\n---\nbuild:\n  script: make\n")},
+		"templates/build/HEADER.md":    {Data: []byte("---\ncategory: Build\n---\nBuilds the project.\n")},
+		"templates/lint.yml":           {Data: []byte("spec:\n  inputs:\n    stage:\n      description: The stage\n---\nlint:\n  script: lint\n")},
+		"FOOTER.md":                    {Data: []byte("## License\n\nMIT\n")},
+	}}
+
+	t.Run("Defaults", func(t *testing.T) {
+		files, err := project.Readme(DefaultReadmeOptions())
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+		assert.Equal(t, "project/README.md", files[0].Path)
+
+		readme := files[0].Content
+		assert.True(t, strings.HasPrefix(readme, "# GitLab CI Components\n\nThis repository contains the following components:\n\n[[_TOC_]]\n\n## Build\n\n### build\n\nBuilds the project.\n"))
+		assert.Contains(t, readme, "\n## Other components\n\n### lint\n")
+		assert.True(t, strings.HasSuffix(readme, "\n## License\n\nMIT\n"))
+		assert.NotContains(t, readme, "| Component |")
+	})
+
+	t.Run("Table of contents and summary", func(t *testing.T) {
+		opts := DefaultReadmeOptions()
+		opts.TOC = true
+		opts.Summary = true
+		files, err := project.Readme(opts)
+		assert.NoError(t, err)
+
+		// the heading of the category comes first, so the component gets a suffix
+		assert.Contains(t, files[0].Content, `components:
+
+- [Build](#build)
+  - [build](#build-1)
+- [Other components](#other-components)
+  - [lint](#lint)
+
+| Component | Description | Mandatory inputs | Optional inputs |
+| --------- | ----------- | ---------------- | --------------- |
+| [build](#build-1) | Builds the project. | 0 | 1 |
+| [lint](#lint) |  | 1 | 0 |
+
+## Build
+`)
+	})
+
+	t.Run("Custom header", func(t *testing.T) {
+		custom := &Project{Root: "project", FS: fstest.MapFS{
+			"templates/lint.yml": {Data: []byte("lint:\n  script: lint\n")},
+			"INTRO.md":           {Data: []byte("# Lint\n\n{{toc}}\n")},
+		}}
+		opts := DefaultReadmeOptions()
+		opts.Header = "INTRO.md"
+		files, err := custom.Readme(opts)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(files[0].Content, "# Lint\n\n- [lint](#lint-1)\n\n## lint\n"))
+	})
+
+	t.Run("Component READMEs", func(t *testing.T) {
+		opts := DefaultReadmeOptions()
+		opts.Output = "project/docs/README.md"
+		opts.ComponentReadme = "index.md"
+		opts.TOC = true
+		files, err := project.Readme(opts)
+		assert.NoError(t, err)
+		assert.Len(t, files, 2)
+		assert.Equal(t, "project/docs/README.md", files[0].Path)
+		assert.Contains(t, files[0].Content, "  - [build](../templates/build/index.md)\n")
+		// single file components are still rendered into the README
+		assert.Contains(t, files[0].Content, "## Build\n\n- [build](../templates/build/index.md)\n\n## Other components\n\n### lint\n")
+
+		assert.Equal(t, "project/templates/build/index.md", files[1].Path)
+		assert.True(t, strings.HasPrefix(files[1].Content, "# build\n\nBuilds the project.\n"))
+	})
+
+	t.Run("Inject", func(t *testing.T) {
+		opts := DefaultReadmeOptions()
+		opts.Inject = true
+		opts.Existing = "# Intro\n\n<!-- BEGIN TOC -->\nold\n<!-- END TOC -->\n\n<!-- BEGIN COMPONENT lint -->\n<!-- END COMPONENT lint -->\n"
+		files, err := project.Readme(opts)
+		assert.NoError(t, err)
+		readme := files[0].Content
+		assert.True(t, strings.HasPrefix(readme, "# Intro\n\n<!-- BEGIN TOC -->\n- [Build](#build)\n  - [build](#build-1)\n"))
+		assert.Contains(t, readme, "<!-- BEGIN COMPONENT lint -->\n### lint\n")
+		assert.NotContains(t, readme, "old")
+		assert.NotContains(t, readme, "License")
+
+		opts.Existing = "<!-- BEGIN TOC -->\n"
+		_, err = project.Readme(opts)
+		assert.ErrorContains(t, err, "project/README.md: ")
+	})
+
+	t.Run("Keep going", func(t *testing.T) {
+		broken := &Project{Root: "project", FS: fstest.MapFS{
+			"templates/lint.yml":  {Data: []byte("lint:\n  script: lint\n")},
+			"templates/build.yml": {Data: []byte("spec: [\n")},
+			"templates/test.yml":  {Data: []byte("test:\n  script: test\n")},
+			"templates/test.md":   {Data: []byte("---\ncategory: [\n---\n")},
+		}}
+		opts := DefaultReadmeOptions()
+		_, err := broken.Readme(opts)
+		assert.ErrorContains(t, err, "project/templates/build.yml")
+		assert.NotContains(t, err.Error(), "project/templates/test.md")
+
+		opts.KeepGoing = true
+		_, err = broken.Readme(opts)
+		assert.ErrorContains(t, err, "project/templates/build.yml")
+		assert.ErrorContains(t, err, "project/templates/test.md")
+	})
+}
@@ -0,0 +1,284 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// functionRegex matches an interpolation function like truncate(0,8)
+var functionRegex = regexp.MustCompile(`^([a-z_]+)(?:\((.*)\))?$`)
+
+// posixSafeRegex matches characters which don't need to be escaped in a POSIX shell
+var posixSafeRegex = regexp.MustCompile(`^[A-Za-z0-9_\-.,:+/@]$`)
+
+// ParseInputValue converts a value given as string, eg. on the command line,
+// into the type of the input
+func (spec *ComponentSpec) ParseInputValue(name, raw string) (any, error) {
+	input, ok := spec.Inputs[name]
+	if !ok || input.Type == "" || input.Type == "string" {
+		return raw, nil
+	}
+
+	var value any
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, fmt.Errorf("input %s: %w", name, err)
+	}
+	return value, nil
+}
+
+// resolveInputs merges the given inputs with the defaults of the spec
+func (spec *ComponentSpec) resolveInputs(inputs map[string]any) (map[string]any, error) {
+	values := map[string]any{}
+	for name, value := range inputs {
+		if _, ok := spec.Inputs[name]; !ok {
+			return nil, fmt.Errorf("unknown input %s", name)
+		}
+		values[name] = value
+	}
+
+	missing := []string{}
+	for name, input := range spec.Inputs {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		if input.Default == nil {
+			missing = append(missing, name)
+			continue
+		}
+		values[name] = input.Default.Value
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing mandatory input(s) %s", strings.Join(missing, ", "))
+	}
+	return values, nil
+}
+
+// Interpolate renders the jobs of a component template, by replacing the
+// interpolations of inputs with the given inputs or their default. Variables
+// are used by the expand_vars function. If spec is nil, the spec of the
+// template is used.
+func Interpolate(b []byte, spec *ComponentSpec, inputs map[string]any, variables map[string]string) (string, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	docs := []*yaml.Node{}
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		docs = append(docs, &doc)
+	}
+
+	if len(docs) > 0 && lookup(docs[0], "spec") != nil {
+		if spec == nil {
+			var header struct {
+				Spec *ComponentSpec `yaml:"spec"`
+			}
+			if err := docs[0].Decode(&header); err != nil {
+				return "", err
+			}
+			spec = header.Spec
+		}
+		docs = docs[1:]
+	}
+	if spec == nil {
+		spec = &ComponentSpec{}
+	}
+
+	values, err := spec.resolveInputs(inputs)
+	if err != nil {
+		return "", err
+	}
+
+	r := &renderer{values: values, variables: variables}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		if err := r.interpolate(doc); err != nil {
+			return "", err
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+type renderer struct {
+	values    map[string]any
+	variables map[string]string
+}
+
+func (r *renderer) interpolate(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode, yaml.MappingNode:
+		for _, child := range node.Content {
+			if err := r.interpolate(child); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return r.interpolateScalar(node)
+	}
+	return nil
+}
+
+func (r *renderer) interpolateScalar(node *yaml.Node) error {
+	matches := interpolationRegex.FindAllStringSubmatchIndex(node.Value, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// if the whole value is a single interpolation without functions, the type of the input is kept
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(node.Value) {
+		i, ok := parseInterpolation(node.Value, node.Value[matches[0][2]:matches[0][3]])
+		if ok && len(i.Functions) == 0 {
+			value, err := r.value(i.Input)
+			if err != nil {
+				return err
+			}
+			if _, isString := value.(string); !isString {
+				var replacement yaml.Node
+				if err := replacement.Encode(value); err != nil {
+					return err
+				}
+				*node = replacement
+				return nil
+			}
+		}
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		sb.WriteString(node.Value[last:m[0]])
+		last = m[1]
+
+		i, ok := parseInterpolation(node.Value[m[0]:m[1]], node.Value[m[2]:m[3]])
+		if !ok {
+			// other contexts like $[[ component.name ]] are kept
+			sb.WriteString(node.Value[m[0]:m[1]])
+			continue
+		}
+
+		value, err := r.value(i.Input)
+		if err != nil {
+			return err
+		}
+		s := formatValue(value)
+		for _, f := range i.Functions {
+			if s, err = r.apply(f, s); err != nil {
+				return fmt.Errorf("%s: %w", i.Expression, err)
+			}
+		}
+		sb.WriteString(s)
+	}
+	sb.WriteString(node.Value[last:])
+
+	node.Value = sb.String()
+	node.Tag = "!!str"
+	// quotes were only needed for the interpolation, the encoder adds them again if required
+	node.Style &^= yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	return nil
+}
+
+func (r *renderer) value(name string) (any, error) {
+	value, ok := r.values[name]
+	if !ok {
+		return nil, fmt.Errorf("input %s is used but not declared in the spec", name)
+	}
+	return value, nil
+}
+
+// formatValue formats a value to be used within a string
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any, map[string]any:
+		b, err := json.Marshal(v)
+		if err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// apply applies an interpolation function on a value
+func (r *renderer) apply(function, value string) (string, error) {
+	m := functionRegex.FindStringSubmatch(function)
+	if m == nil {
+		return "", fmt.Errorf("invalid function %s", function)
+	}
+
+	switch m[1] {
+	case "expand_vars":
+		return os.Expand(value, func(name string) string {
+			if v, ok := r.variables[name]; ok {
+				return v
+			}
+			// unknown variables are kept as they are
+			return "$" + name
+		}), nil
+	case "truncate":
+		args := strings.Split(m[2], ",")
+		if len(args) != 2 {
+			return "", fmt.Errorf("truncate requires an offset and a length")
+		}
+		offset, err := strconv.Atoi(strings.TrimSpace(args[0]))
+		if err != nil || offset < 0 {
+			return "", fmt.Errorf("invalid offset %q", args[0])
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(args[1]))
+		if err != nil || length < 0 {
+			return "", fmt.Errorf("invalid length %q", args[1])
+		}
+		runes := []rune(value)
+		offset = min(offset, len(runes))
+		return string(runes[offset:min(offset+length, len(runes))]), nil
+	case "posix_escape":
+		return posixEscape(value), nil
+	}
+	return "", fmt.Errorf("unknown function %s", m[1])
+}
+
+// posixEscape escapes a value to be safely used as a word in a POSIX shell
+func posixEscape(value string) string {
+	if value == "" {
+		return "''"
+	}
+	var sb strings.Builder
+	for _, c := range value {
+		switch {
+		case c == '\n':
+			sb.WriteString("'\n'")
+		case posixSafeRegex.MatchString(string(c)):
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune('\\')
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
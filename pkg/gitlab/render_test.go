@@ -0,0 +1,107 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Interpolate(t *testing.T) {
+	template := `spec:
+  inputs:
+    job-prefix:
+    stage:
+      default: test
+    concurrency:
+      type: number
+      default: 1
+    paths:
+      type: array
+      default: [dist]
+    message:
+      default: "Hello $NAME, it's ${DAY}"
+---
+"$[[ inputs.job-prefix ]]-build":
+  stage: $[[ inputs.stage ]]
+  parallel: $[[ inputs.concurrency ]]
+  script:
+    - echo $[[ inputs.message | expand_vars | posix_escape ]]
+    - echo $[[ inputs.job-prefix | truncate(1,3) ]] $[[ component.name ]]
+  artifacts:
+    paths: $[[ inputs.paths ]]
+`
+
+	t.Run("Defaults", func(t *testing.T) {
+		expected := `my-job-build:
+  stage: test
+  parallel: 1
+  script:
+    - echo Hello\ World,\ it\'s\ \$DAY
+    - echo y-j $[[ component.name ]]
+  artifacts:
+    paths:
+      - dist
+`
+		result, err := Interpolate([]byte(template), nil, map[string]any{"job-prefix": "my-job"}, map[string]string{"NAME": "World"})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Inputs", func(t *testing.T) {
+		expected := `a-build:
+  stage: "true"
+  parallel: 5
+  script:
+    - echo Hello\ \$NAME,\ it\'s\ \$DAY
+    - echo  $[[ component.name ]]
+  artifacts:
+    paths:
+      - a
+      - b
+`
+		inputs := map[string]any{"job-prefix": "a", "stage": "true", "concurrency": 5, "paths": []any{"a", "b"}}
+		result, err := Interpolate([]byte(template), nil, inputs, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Missing input", func(t *testing.T) {
+		_, err := Interpolate([]byte(template), nil, nil, nil)
+		assert.EqualError(t, err, "missing mandatory input(s) job-prefix")
+	})
+
+	t.Run("Unknown input", func(t *testing.T) {
+		_, err := Interpolate([]byte(template), nil, map[string]any{"job-prefix": "a", "other": "b"}, nil)
+		assert.EqualError(t, err, "unknown input other")
+	})
+
+	t.Run("Undeclared input", func(t *testing.T) {
+		_, err := Interpolate([]byte("spec:\n---\njob:\n  script: echo $[[ inputs.name ]]\n"), nil, nil, nil)
+		assert.EqualError(t, err, "input name is used but not declared in the spec")
+	})
+
+	t.Run("Unknown function", func(t *testing.T) {
+		_, err := Interpolate([]byte("spec:\n  inputs:\n    name:\n---\njob:\n  script: echo $[[ inputs.name | upper ]]\n"), nil, map[string]any{"name": "a"}, nil)
+		assert.EqualError(t, err, "$[[ inputs.name | upper ]]: unknown function upper")
+	})
+}
+
+func Test_ParseInputValue(t *testing.T) {
+	spec := &ComponentSpec{Inputs: map[string]ComponentInput{
+		"string": {},
+		"number": {Type: "number"},
+		"array":  {Type: "array"},
+	}}
+
+	value, err := spec.ParseInputValue("string", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", value)
+
+	value, err = spec.ParseInputValue("number", "1.5")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, value)
+
+	value, err = spec.ParseInputValue("array", "[a, b]")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"a", "b"}, value)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// CodeQualityIssue is a finding in the format of a GitLab Code Quality report,
+// which is a subset of the Code Climate format
+type CodeQualityIssue struct {
+	Type        string              `json:"type"`
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    CodeQualityLocation `json:"location"`
+}
+
+type CodeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeQualityLines `json:"lines"`
+}
+
+type CodeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// codeQualitySeverity maps the severity of a finding to the Code Quality severities
+// info, minor, major, critical and blocker
+var codeQualitySeverity = map[Severity]string{
+	SeverityError:   "major",
+	SeverityWarning: "minor",
+}
+
+// CodeQuality converts the findings into the issues of a GitLab Code Quality report.
+// The paths are relative to root, which is usually the project directory.
+func CodeQuality(findings []Finding, root string) []CodeQualityIssue {
+	fingerprints := Fingerprints(findings, root)
+	issues := make([]CodeQualityIssue, len(findings))
+	for i, f := range findings {
+		issues[i] = CodeQualityIssue{
+			Type:        "issue",
+			Description: f.Message,
+			CheckName:   f.Rule,
+			Fingerprint: fingerprints[i],
+			Severity:    codeQualitySeverity[f.Severity],
+			Location: CodeQualityLocation{
+				Path:  relativePath(root, f.File),
+				Lines: CodeQualityLines{Begin: f.Line},
+			},
+		}
+	}
+	return issues
+}
+
+// Fingerprints identify the findings across runs. The line is not part of them,
+// so a finding is still recognized if lines are added above it. Identical
+// findings within a file are told apart by the order they occur in.
+func Fingerprints(findings []Finding, root string) []string {
+	fingerprints := make([]string, len(findings))
+	occurrences := map[string]int{}
+	for i, f := range findings {
+		key := strings.Join([]string{relativePath(root, f.File), f.Rule, f.Message}, "\x00")
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, occurrences[key])))
+		occurrences[key]++
+		fingerprints[i] = hex.EncodeToString(sum[:])
+	}
+	return fingerprints
+}
+
+// relativePath returns the path of the file relative to root with forward
+// slashes. Relative paths are relative to the current directory. Files outside
+// of root are returned unchanged.
+func relativePath(root, file string) string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	if rel, err := filepath.Rel(absRoot, absFile); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(file)
+}
+
+// MarshalFindings encodes the findings in the given format, either text,
+// codequality or sarif. The paths of the reports are relative to root, the text
+// keeps them as they are.
+func MarshalFindings(findings []Finding, format, root string) ([]byte, error) {
+	switch format {
+	case "text":
+		var sb strings.Builder
+		for _, f := range findings {
+			sb.WriteString(f.String() + "\n")
+		}
+		return []byte(sb.String()), nil
+	case "codequality":
+		return marshalIndent(CodeQuality(findings, root))
+	case "sarif":
+		return marshalIndent(NewSARIF(findings, root))
+	}
+	return nil, fmt.Errorf("unsupported format %q, supported are text, codequality and sarif", format)
+}
+
+func marshalIndent(v any) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
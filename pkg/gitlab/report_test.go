@@ -0,0 +1,106 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var reportFindings = []Finding{
+	{File: "project/templates/build/template.yml", Line: 4, Column: 13, Severity: SeverityError, Rule: RuleUnknownType, Message: `input stage has unknown type "strin"`},
+	{File: "project/templates/build/template.yml", Line: 3, Column: 5, Severity: SeverityWarning, Rule: RuleUnusedInput, Message: "input stage is declared but never used"},
+}
+
+func Test_CodeQuality(t *testing.T) {
+	issues := CodeQuality(reportFindings, "project")
+
+	assert.Len(t, issues, 2)
+	assert.Equal(t, CodeQualityIssue{
+		Type:        "issue",
+		Description: `input stage has unknown type "strin"`,
+		CheckName:   "unknown-type",
+		Fingerprint: Fingerprints(reportFindings, "project")[0],
+		Severity:    "major",
+		Location:    CodeQualityLocation{Path: "templates/build/template.yml", Lines: CodeQualityLines{Begin: 4}},
+	}, issues[0])
+	assert.Equal(t, "minor", issues[1].Severity)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+}
+
+func Test_Fingerprints(t *testing.T) {
+	fingerprint := Fingerprints(reportFindings, "project")[0]
+	assert.Len(t, fingerprint, 64)
+
+	moved := reportFindings[0]
+	moved.Line = 10
+	assert.Equal(t, fingerprint, Fingerprints([]Finding{moved}, "project")[0])
+
+	other := reportFindings[0]
+	other.File = "project/templates/deploy/template.yml"
+	assert.NotEqual(t, fingerprint, Fingerprints([]Finding{other}, "project")[0])
+
+	// the location of the project doesn't change the fingerprint
+	checkout := reportFindings[0]
+	checkout.File = "/builds/group/project/templates/build/template.yml"
+	assert.Equal(t, fingerprint, Fingerprints([]Finding{checkout}, "/builds/group/project")[0])
+
+	repeated := Fingerprints([]Finding{reportFindings[0], moved, reportFindings[0]}, "project")
+	assert.Equal(t, fingerprint, repeated[0])
+	assert.NotEqual(t, repeated[0], repeated[1])
+	assert.NotEqual(t, repeated[1], repeated[2])
+	assert.NotEqual(t, repeated[0], repeated[2])
+}
+
+func Test_relativePath(t *testing.T) {
+	assert.Equal(t, "templates/build.yml", relativePath(".", "templates/build.yml"))
+	assert.Equal(t, "templates/build.yml", relativePath("project", "project/templates/build.yml"))
+	assert.Equal(t, "templates/build.yml", relativePath("release.tar.gz", "release.tar.gz/templates/build.yml"))
+	assert.Equal(t, "../.gitlab-ci.yml", relativePath("project", "../.gitlab-ci.yml"))
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, "components/templates/build.yml", relativePath(".", filepath.Join(wd, "components", "templates", "build.yml")))
+	assert.Equal(t, "components/templates/build.yml", relativePath(".", "components/templates/build.yml"))
+}
+
+func Test_MarshalFindings(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		b, err := MarshalFindings(reportFindings, "text", "project")
+		assert.NoError(t, err)
+		assert.Equal(t, reportFindings[0].String()+"\n"+reportFindings[1].String()+"\n", string(b))
+	})
+
+	t.Run("Code Quality", func(t *testing.T) {
+		b, err := MarshalFindings(reportFindings, "codequality", "project")
+		assert.NoError(t, err)
+
+		var issues []map[string]any
+		assert.NoError(t, json.Unmarshal(b, &issues))
+		assert.Len(t, issues, 2)
+		assert.Equal(t, map[string]any{"path": "templates/build/template.yml", "lines": map[string]any{"begin": float64(4)}}, issues[0]["location"])
+	})
+
+	t.Run("SARIF", func(t *testing.T) {
+		b, err := MarshalFindings(reportFindings, "sarif", "project")
+		assert.NoError(t, err)
+
+		var report map[string]any
+		assert.NoError(t, json.Unmarshal(b, &report))
+		assert.Equal(t, "2.1.0", report["version"])
+		assert.Len(t, report["runs"], 1)
+	})
+
+	t.Run("No findings", func(t *testing.T) {
+		b, err := MarshalFindings(nil, "codequality", ".")
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", string(b))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := MarshalFindings(reportFindings, "xml", "project")
+		assert.EqualError(t, err, `unsupported format "xml", supported are text, codequality and sarif`)
+	})
+}
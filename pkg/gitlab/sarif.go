@@ -0,0 +1,129 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+// SARIF is a report in the Static Analysis Results Interchange Format 2.1.0
+type SARIF struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// Rules describes all rules findings are reported for
+var Rules = []SARIFRule{
+	{ID: RuleYamlSyntax, ShortDescription: SARIFMessage{"The template is not valid YAML"}},
+	{ID: RuleUnknownType, ShortDescription: SARIFMessage{"The type of an input is not supported"}},
+	{ID: RuleUnknownKey, ShortDescription: SARIFMessage{"An input has an unknown key"}},
+	{ID: RuleInvalidRegex, ShortDescription: SARIFMessage{"The regex of an input doesn't compile"}},
+	{ID: RuleInvalidKeyValue, ShortDescription: SARIFMessage{"A key of an input has a value of the wrong kind"}},
+	{ID: RuleDefaultNotInOptions, ShortDescription: SARIFMessage{"The default of an input is not one of its options"}},
+	{ID: RuleDefaultNoRegexMatch, ShortDescription: SARIFMessage{"The default of an input doesn't match its regex"}},
+	{ID: RuleDefaultWrongType, ShortDescription: SARIFMessage{"The default of an input doesn't match its type"}},
+	{ID: RuleUnusedInput, ShortDescription: SARIFMessage{"An input is declared, but never used"}},
+	{ID: RuleUndeclaredInput, ShortDescription: SARIFMessage{"An interpolation uses an input which is not declared"}},
+	{ID: RuleInvalidInclude, ShortDescription: SARIFMessage{"A spec:include can't be resolved"}},
+	{ID: RuleUnknownComponent, ShortDescription: SARIFMessage{"An included component doesn't exist in the project"}},
+	{ID: RuleMissingInput, ShortDescription: SARIFMessage{"A mandatory input is not passed to a component"}},
+	{ID: RuleUnknownInput, ShortDescription: SARIFMessage{"An input passed to a component doesn't exist"}},
+	{ID: RuleInputNotInOptions, ShortDescription: SARIFMessage{"An input value is not one of the options"}},
+	{ID: RuleInputNoRegexMatch, ShortDescription: SARIFMessage{"An input value doesn't match the regex"}},
+	{ID: RuleInputWrongType, ShortDescription: SARIFMessage{"An input value doesn't match the type"}},
+}
+
+// sarifLevel maps the severity of a finding to the SARIF levels
+var sarifLevel = map[Severity]string{
+	SeverityError:   "error",
+	SeverityWarning: "warning",
+}
+
+// NewSARIF converts the findings into a SARIF report with a single run. The
+// paths are relative to root, which is usually the project directory.
+func NewSARIF(findings []Finding, root string) SARIF {
+	index := map[string]int{}
+	for i, r := range Rules {
+		index[r.ID] = i
+	}
+
+	fingerprints := Fingerprints(findings, root)
+	results := make([]SARIFResult, len(findings))
+	for i, f := range findings {
+		results[i] = SARIFResult{
+			RuleID:    f.Rule,
+			RuleIndex: index[f.Rule],
+			Level:     sarifLevel[f.Severity],
+			Message:   SARIFMessage{f.Message},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: relativePath(root, f.File)},
+					Region:           SARIFRegion{StartLine: f.Line, StartColumn: f.Column},
+				},
+			}},
+			PartialFingerprints: map[string]string{"findingHash/v1": fingerprints[i]},
+		}
+	}
+
+	return SARIF{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "glab-component-generator",
+				InformationURI: "https://github.com/peschmae/glab-component-generator",
+				Rules:          Rules,
+			}},
+			Results: results,
+		}},
+	}
+}
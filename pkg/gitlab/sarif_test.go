@@ -0,0 +1,46 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewSARIF(t *testing.T) {
+	report := NewSARIF(reportFindings, "project")
+
+	assert.Equal(t, "2.1.0", report.Version)
+	assert.Len(t, report.Runs, 1)
+	run := report.Runs[0]
+	assert.Equal(t, "glab-component-generator", run.Tool.Driver.Name)
+	assert.Len(t, run.Results, 2)
+
+	result := run.Results[0]
+	assert.Equal(t, "unknown-type", result.RuleID)
+	assert.Equal(t, "unknown-type", run.Tool.Driver.Rules[result.RuleIndex].ID)
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, `input stage has unknown type "strin"`, result.Message.Text)
+	assert.Equal(t, SARIFPhysicalLocation{
+		ArtifactLocation: SARIFArtifactLocation{URI: "templates/build/template.yml"},
+		Region:           SARIFRegion{StartLine: 4, StartColumn: 13},
+	}, result.Locations[0].PhysicalLocation)
+	assert.Equal(t, Fingerprints(reportFindings, "project")[0], result.PartialFingerprints["findingHash/v1"])
+
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Equal(t, "unused-input", run.Tool.Driver.Rules[run.Results[1].RuleIndex].ID)
+}
+
+func Test_Rules(t *testing.T) {
+	ids := map[string]bool{}
+	for _, r := range Rules {
+		assert.False(t, ids[r.ID], "duplicate rule %s", r.ID)
+		assert.NotEmpty(t, r.ShortDescription.Text)
+		ids[r.ID] = true
+	}
+}
+
+func Test_NewSARIFWithoutFindings(t *testing.T) {
+	report := NewSARIF(nil, ".")
+	assert.NotNil(t, report.Runs[0].Results)
+	assert.Empty(t, report.Runs[0].Results)
+}
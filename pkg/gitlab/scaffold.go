@@ -0,0 +1,131 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InputDefinition is a short definition of an input used to scaffold a component
+type InputDefinition struct {
+	Name       string
+	Type       string
+	Default    string
+	HasDefault bool
+}
+
+// ValidateComponentName checks that the name of a new component is a single
+// path element, so the component is created within the templates directory
+func ValidateComponentName(name string) error {
+	if name == "" || name == "." || strings.ContainsAny(name, `/\`) || filepath.Base(name) != name || !filepath.IsLocal(name) {
+		return fmt.Errorf("component name %q must be a single path element", name)
+	}
+	return nil
+}
+
+// ParseInputDefinitions parses a comma separated list of inputs in the format
+// name[:type[:default]]. Commas within brackets are part of the default, eg.
+// paths:array:[a, b].
+func ParseInputDefinitions(definitions string) ([]InputDefinition, error) {
+	inputs := []InputDefinition{}
+	if strings.TrimSpace(definitions) == "" {
+		return inputs, nil
+	}
+
+	for _, definition := range splitDefinitions(definitions) {
+		parts := strings.SplitN(strings.TrimSpace(definition), ":", 3)
+		input := InputDefinition{Name: parts[0]}
+		if input.Name == "" {
+			return nil, fmt.Errorf("input %q has no name", definition)
+		}
+		if len(parts) > 1 {
+			input.Type = parts[1]
+			if input.Type != "" && !slices.Contains(inputTypes, input.Type) {
+				return nil, fmt.Errorf("input %s has unknown type %q, supported are %s", input.Name, input.Type, strings.Join(inputTypes, ", "))
+			}
+		}
+		if len(parts) > 2 {
+			input.Default = parts[2]
+			input.HasDefault = true
+		}
+		inputs = append(inputs, input)
+	}
+
+	return inputs, nil
+}
+
+// splitDefinitions splits the definitions at commas, which are not within
+// brackets or braces
+func splitDefinitions(definitions string) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	for i, r := range definitions {
+		switch r {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, definitions[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, definitions[start:])
+}
+
+// ScaffoldTemplate returns the template of a new component with the given inputs
+func ScaffoldTemplate(name string, inputs []InputDefinition) string {
+	var sb strings.Builder
+
+	sb.WriteString("spec:\n")
+	if len(inputs) == 0 {
+		sb.WriteString("  inputs: {}\n")
+	} else {
+		sb.WriteString("  inputs:\n")
+	}
+	for _, input := range inputs {
+		sb.WriteString(fmt.Sprintf("    %s:\n", input.Name))
+		sb.WriteString("      description: \"\"\n")
+		if input.Type != "" {
+			sb.WriteString(fmt.Sprintf("      type: %s\n", input.Type))
+		}
+		if input.HasDefault {
+			sb.WriteString(fmt.Sprintf("      default: %s\n", scaffoldDefault(input)))
+		}
+	}
+
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("%s:\n", name))
+	sb.WriteString("  script:\n")
+	if len(inputs) == 0 {
+		sb.WriteString(fmt.Sprintf("    - echo \"Hello from %s\"\n", name))
+	}
+	for _, input := range inputs {
+		// a ": " would turn the item into a mapping
+		sb.WriteString(fmt.Sprintf("    - echo \"%s=$[[ inputs.%s ]]\"\n", input.Name, input.Name))
+	}
+
+	return sb.String()
+}
+
+// scaffoldDefault formats the default of an input, strings are quoted if necessary
+func scaffoldDefault(input InputDefinition) string {
+	if input.Type != "" && input.Type != "string" {
+		return input.Default
+	}
+	b, err := yaml.Marshal(input.Default)
+	if err != nil {
+		return input.Default
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
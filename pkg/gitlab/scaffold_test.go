@@ -0,0 +1,119 @@
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_ParseInputDefinitions(t *testing.T) {
+	inputs, err := ParseInputDefinitions("job-prefix, stage::test,concurrency:number:1,empty:string:,paths:array")
+	assert.NoError(t, err)
+	assert.Equal(t, []InputDefinition{
+		{Name: "job-prefix"},
+		{Name: "stage", Default: "test", HasDefault: true},
+		{Name: "concurrency", Type: "number", Default: "1", HasDefault: true},
+		{Name: "empty", Type: "string", Default: "", HasDefault: true},
+		{Name: "paths", Type: "array"},
+	}, inputs)
+
+	inputs, err = ParseInputDefinitions("paths:array:[a, b],env::test,config:string:{a: [1, 2]}")
+	assert.NoError(t, err)
+	assert.Equal(t, []InputDefinition{
+		{Name: "paths", Type: "array", Default: "[a, b]", HasDefault: true},
+		{Name: "env", Default: "test", HasDefault: true},
+		{Name: "config", Type: "string", Default: "{a: [1, 2]}", HasDefault: true},
+	}, inputs)
+
+	inputs, err = ParseInputDefinitions("")
+	assert.NoError(t, err)
+	assert.Empty(t, inputs)
+
+	_, err = ParseInputDefinitions("a:strin")
+	assert.EqualError(t, err, `input a has unknown type "strin", supported are string, number, boolean, array`)
+
+	_, err = ParseInputDefinitions("a,:string")
+	assert.EqualError(t, err, `input ":string" has no name`)
+}
+
+func Test_ValidateComponentName(t *testing.T) {
+	assert.NoError(t, ValidateComponentName("build"))
+	assert.NoError(t, ValidateComponentName("build.v2"))
+	for _, name := range []string{"", ".", "..", "a/b", `a\b`, "/build"} {
+		assert.EqualError(t, ValidateComponentName(name), fmt.Sprintf("component name %q must be a single path element", name))
+	}
+}
+
+func Test_ScaffoldTemplate(t *testing.T) {
+	t.Run("Inputs", func(t *testing.T) {
+		inputs := []InputDefinition{
+			{Name: "stage", Default: "test", HasDefault: true},
+			{Name: "enabled", Type: "boolean", Default: "true", HasDefault: true},
+			{Name: "empty", Default: "", HasDefault: true},
+			{Name: "image"},
+		}
+
+		expected := `spec:
+  inputs:
+    stage:
+      description: ""
+      default: test
+    enabled:
+      description: ""
+      type: boolean
+      default: true
+    empty:
+      description: ""
+      default: ""
+    image:
+      description: ""
+---
+deploy:
+  script:
+    - echo "stage=$[[ inputs.stage ]]"
+    - echo "enabled=$[[ inputs.enabled ]]"
+    - echo "empty=$[[ inputs.empty ]]"
+    - echo "image=$[[ inputs.image ]]"
+`
+		template := ScaffoldTemplate("deploy", inputs)
+		assert.Equal(t, expected, template)
+		assert.Empty(t, LintTemplate("template.yml", []byte(template)))
+		assertScriptStrings(t, template)
+	})
+
+	t.Run("Without inputs", func(t *testing.T) {
+		expected := `spec:
+  inputs: {}
+---
+deploy:
+  script:
+    - echo "Hello from deploy"
+`
+		template := ScaffoldTemplate("deploy", nil)
+		assert.Equal(t, expected, template)
+		assert.Empty(t, LintTemplate("template.yml", []byte(template)))
+		assertScriptStrings(t, template)
+	})
+}
+
+// assertScriptStrings checks that each script line of the jobs is a string
+func assertScriptStrings(t *testing.T, template string) {
+	t.Helper()
+	decoder := yaml.NewDecoder(strings.NewReader(template))
+	var spec any
+	assert.NoError(t, decoder.Decode(&spec))
+	var jobs map[string]struct {
+		Script []any `yaml:"script"`
+	}
+	assert.NoError(t, decoder.Decode(&jobs))
+	assert.NotEmpty(t, jobs)
+	for name, job := range jobs {
+		assert.NotEmpty(t, job.Script, name)
+		for _, line := range job.Script {
+			assert.IsType(t, "", line, "script of %s", name)
+		}
+	}
+}
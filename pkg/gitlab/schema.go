@@ -0,0 +1,70 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"sort"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema needed to describe the inputs of a component
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Enum                 []Value                `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Default              *Value                 `json:"default,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema validating the inputs passed to the component
+func (c *Component) JSONSchema() *JSONSchema {
+	additional := false
+	schema := &JSONSchema{
+		Schema:               jsonSchemaDraft,
+		Title:                c.Name,
+		Description:          fmt.Sprintf("Inputs of the component %s", c.Name),
+		Type:                 "object",
+		Properties:           map[string]*JSONSchema{},
+		AdditionalProperties: &additional,
+	}
+
+	if c.Spec == nil {
+		return schema
+	}
+
+	for name, input := range c.Spec.Inputs {
+		schema.Properties[name] = input.JSONSchema()
+		if input.Default == nil {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+// JSONSchema returns a JSON Schema validating the value of the input
+func (input ComponentInput) JSONSchema() *JSONSchema {
+	schema := &JSONSchema{
+		Description: input.Description,
+		Type:        input.Type,
+		Enum:        input.Options,
+		Default:     input.Default,
+	}
+	// GitLab defaults to string inputs
+	if schema.Type == "" {
+		schema.Type = "string"
+	}
+	if input.Regex != "" {
+		schema.Pattern, _ = RegexPattern(input.Regex)
+	}
+	return schema
+}
@@ -0,0 +1,87 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_JSONSchema(t *testing.T) {
+	component := &Component{Name: "deploy"}
+	yaml.Unmarshal([]byte(`
+spec:
+  inputs:
+    job-prefix:
+      description: "Define a prefix for the job name"
+    environment:
+      options: ['test', 'staging', 'production']
+    concurrency:
+      type: number
+      default: 1
+    version:
+      type: string
+      regex: /^v\d\.\d+(\.\d+)$/
+    export_results:
+      type: boolean
+      default: true
+    paths:
+      type: array
+      default: [a]`), component)
+
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "deploy",
+  "description": "Inputs of the component deploy",
+  "type": "object",
+  "properties": {
+    "concurrency": {
+      "type": "number",
+      "default": 1
+    },
+    "environment": {
+      "type": "string",
+      "enum": [
+        "test",
+        "staging",
+        "production"
+      ]
+    },
+    "export_results": {
+      "type": "boolean",
+      "default": true
+    },
+    "job-prefix": {
+      "description": "Define a prefix for the job name",
+      "type": "string"
+    },
+    "paths": {
+      "type": "array",
+      "default": [
+        "a"
+      ]
+    },
+    "version": {
+      "type": "string",
+      "pattern": "^v\\d\\.\\d+(\\.\\d+)$"
+    }
+  },
+  "required": [
+    "environment",
+    "job-prefix",
+    "version"
+  ],
+  "additionalProperties": false
+}`
+
+	b, err := json.MarshalIndent(component.JSONSchema(), "", "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(b))
+
+	t.Run("Without spec", func(t *testing.T) {
+		b, err := json.Marshal((&Component{Name: "empty"}).JSONSchema())
+		assert.NoError(t, err)
+		assert.Equal(t, `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"empty","description":"Inputs of the component empty","type":"object","additionalProperties":false}`, string(b))
+	})
+}
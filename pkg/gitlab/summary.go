@@ -0,0 +1,98 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegionSummary is the name of the region containing the summary table
+const RegionSummary = "SUMMARY"
+
+// sentenceEnd matches the end of the first sentence of a paragraph
+var sentenceEnd = regexp.MustCompile(`[.!?](\s|$)`)
+
+// nonParagraph matches lines which don't belong to a paragraph, like headings,
+// list items, tables, block quotes and HTML
+var nonParagraph = regexp.MustCompile(`^(#|[-*+]\s|\d+[.)]\s|\||>|<)`)
+
+// Summary returns the description of the component, or the first sentence of
+// its header if there is none. It is collapsed into a single line.
+func (c *Component) Summary() string {
+	if c.Description != "" {
+		return strings.Join(strings.Fields(c.Description), " ")
+	}
+	return firstSentence(c.Header)
+}
+
+// firstSentence returns the first sentence of the first paragraph of the
+// markdown, skipping headings, lists, tables, HTML and fenced code
+func firstSentence(markdown string) string {
+	var paragraph []string
+	fence := ""
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fence = line[:3]
+		}
+		if line == "" || fence != "" || nonParagraph.MatchString(line) {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+
+	text := strings.Join(strings.Fields(strings.Join(paragraph, " ")), " ")
+	if loc := sentenceEnd.FindStringIndex(text); loc != nil {
+		text = text[:loc[0]+1]
+	}
+	return text
+}
+
+// InputCounts returns the number of mandatory and optional inputs
+func (spec *ComponentSpec) InputCounts() (mandatory, optional int) {
+	if spec == nil {
+		return 0, 0
+	}
+	for _, input := range spec.Inputs {
+		if input.Default == nil {
+			mandatory++
+		} else {
+			optional++
+		}
+	}
+	return mandatory, optional
+}
+
+// SummaryTable renders a table listing each component with its summary and the
+// number of its inputs. The components are grouped by category, like their
+// sections. The links are the targets of the component names, eg. the anchors
+// of their sections.
+func SummaryTable(components []*Component, links map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("| Component | Description | Mandatory inputs | Optional inputs |\n")
+	sb.WriteString("| --------- | ----------- | ---------------- | --------------- |\n")
+	// the rows are in the same order as the sections of the components
+	for _, group := range GroupComponents(components) {
+		for _, c := range group.Components {
+			name := c.Name
+			if link, ok := links[c.Name]; ok {
+				name = fmt.Sprintf("[%s](%s)", c.Name, link)
+			}
+			mandatory, optional := c.Spec.InputCounts()
+			fmt.Fprintf(&sb, "| %s | %s | %d | %d |\n", name, escapeCell(c.Summary()), mandatory, optional)
+		}
+	}
+	return sb.String()
+}
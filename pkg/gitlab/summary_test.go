@@ -0,0 +1,70 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Summary(t *testing.T) {
+	tests := []struct {
+		name      string
+		component Component
+		expected  string
+	}{
+		{"Description", Component{Description: " Builds the project ", Header: "Something else."}, "Builds the project"},
+		{"First sentence", Component{Header: "Builds the project. Uses Kaniko.\n"}, "Builds the project."},
+		{"Heading", Component{Header: "# Build\n\nBuilds the\nproject! Uses Kaniko.\n"}, "Builds the project!"},
+		{"Version number", Component{Header: "Supports v1.2 and later\n\nSecond paragraph.\n"}, "Supports v1.2 and later"},
+		{"Multi-line description", Component{Description: "Builds the\nproject\n\n  using Kaniko\n"}, "Builds the project using Kaniko"},
+		{"Fenced code", Component{Header: "```yaml\ninclude: build\n```\n\nBuilds the project.\n"}, "Builds the project."},
+		{"List", Component{Header: "- Builds\n- Pushes\n\nBuilds the project.\n"}, "Builds the project."},
+		{"HTML", Component{Header: "<img src=\"logo.png\">\n\nBuilds the project.\n"}, "Builds the project."},
+		{"Table", Component{Header: "| a | b |\n\nBuilds the project.\n"}, "Builds the project."},
+		{"Ends before list", Component{Header: "Builds the project\n- fast\n"}, "Builds the project"},
+		{"Empty", Component{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.component.Summary())
+		})
+	}
+}
+
+func Test_SummaryTable(t *testing.T) {
+	build := &Component{Name: "build", Header: "Builds the project | fast.\n"}
+	yaml.Unmarshal([]byte(`
+spec:
+  inputs:
+    image:
+    stage:
+      default: build
+    tags:
+      default: []
+`), build)
+	lint := &Component{Name: "lint", Description: "Lints the\nproject\n"}
+
+	expected := `| Component | Description | Mandatory inputs | Optional inputs |
+| --------- | ----------- | ---------------- | --------------- |
+| [build](#build) | Builds the project \| fast. | 1 | 2 |
+| lint | Lints the project | 0 | 0 |
+`
+	assert.Equal(t, expected, SummaryTable([]*Component{build, lint}, map[string]string{"build": "#build"}))
+}
+
+func Test_SummaryTableGrouped(t *testing.T) {
+	components := []*Component{
+		{Name: "build", Category: "Build"},
+		{Name: "lint"},
+		{Name: "docker", Category: "Build"},
+	}
+
+	expected := `| Component | Description | Mandatory inputs | Optional inputs |
+| --------- | ----------- | ---------------- | --------------- |
+| build |  | 0 | 0 |
+| docker |  | 0 | 0 |
+| lint |  | 0 | 0 |
+`
+	assert.Equal(t, expected, SummaryTable(components, nil))
+}
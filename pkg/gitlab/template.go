@@ -0,0 +1,58 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultTemplate is used to render a component, if no other template is given
+//
+//go:embed component.md.tmpl
+var DefaultTemplate string
+
+var defaultTemplate = template.Must(NewTemplate("default", DefaultTemplate))
+
+// MandatoryMarker is rendered as default value for inputs without a default
+var MandatoryMarker = fmt.Sprintf("%c", '\U000026D4')
+
+// templateFuncs are available in all templates, in addition to the methods of the model
+var templateFuncs = template.FuncMap{
+	"headerLevel": func() string { return "" },
+	"trim":        strings.TrimSpace,
+	"br":          replaceLinebreaks,
+	"join":        strings.Join,
+	"code": func(s string) string {
+		return fmt.Sprintf("`%s`", s)
+	},
+	"escape":    escapeCell,
+	"jobsTable": JobsMarkdownTable,
+	"usage":     func(*Component) string { return "" },
+}
+
+// templateFuncs returns the functions depending on the options, replacing the
+// placeholders in templateFuncs when a component is rendered
+func (o Options) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"headerLevel": func() string {
+			return strings.Repeat("#", o.HeaderLevel)
+		},
+		// usage returns the usage snippet of the component, if enabled
+		"usage": func(c *Component) string {
+			if o.Usage == nil {
+				return ""
+			}
+			return c.Usage(*o.Usage)
+		},
+	}
+}
+
+// NewTemplate parses a template used to render a component. The template is
+// executed with the *Component.
+func NewTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
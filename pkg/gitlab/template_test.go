@@ -0,0 +1,64 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Render(t *testing.T) {
+	opts := Options{HeaderLevel: 2}
+
+	component := &Component{Name: "test", Header: "Some\nheader\n", Jobs: []Job{{Name: "build", Stage: "build"}}}
+	yaml.Unmarshal([]byte(`
+spec:
+  inputs:
+    stage:
+      default: test
+      description: |
+        The stage
+        of the job
+    image:
+      options: [alpine, debian]`), component)
+
+	t.Run("Custom template", func(t *testing.T) {
+		tmpl, err := NewTemplate("custom", `{{ headerLevel }}# {{ .Name }}
+{{ range .Spec.SortedInputs }}
+- {{ code .Name }}: {{ br .Description }} ({{ if .Default }}{{ .Default.String }}{{ else }}mandatory, one of {{ join .OptionsStrings ", " }}{{ end }})
+{{- end }}
+{{ range .Jobs }}
+- {{ .Name }} in {{ .Stage }}
+{{- end }}
+`)
+		assert.NoError(t, err)
+
+		expected := "### test\n\n- `image`:  (mandatory, one of alpine, debian)\n- `stage`: The stage<br>of the job (test)\n\n- build in build\n"
+
+		md, err := component.Render(tmpl, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, md)
+	})
+
+	t.Run("Default template", func(t *testing.T) {
+		tmpl, err := NewTemplate("default", DefaultTemplate)
+		assert.NoError(t, err)
+
+		md, err := component.Render(tmpl, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, component.Markdown(opts), md)
+	})
+
+	t.Run("Execution error", func(t *testing.T) {
+		tmpl, err := NewTemplate("broken", `{{ .Missing }}`)
+		assert.NoError(t, err)
+
+		_, err = component.Render(tmpl, opts)
+		assert.ErrorContains(t, err, "component test: ")
+	})
+
+	t.Run("Parse error", func(t *testing.T) {
+		_, err := NewTemplate("broken", `{{ .Name `)
+		assert.Error(t, err)
+	})
+}
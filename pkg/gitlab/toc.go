@@ -0,0 +1,173 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TOCPlaceholder is replaced with the table of contents in a custom header
+const TOCPlaceholder = "{{toc}}"
+
+// RegionTOC is the name of the region containing the table of contents
+const RegionTOC = "TOC"
+
+// TOCEntry is an item of a table of contents
+type TOCEntry struct {
+	Title string
+	// Link is the target of the entry. If it is empty, the entry links to the
+	// heading with the title within the same document.
+	Link string
+	// Children are rendered as nested list below the entry
+	Children []TOCEntry
+	// Offset is the position of the section of the entry within the rendered
+	// markdown. The first heading at or after it is the target of the entry.
+	Offset int
+}
+
+// Heading is a heading of a markdown document
+type Heading struct {
+	Title string
+	// Anchor is the ID GitLab generates for the heading, without the leading #
+	Anchor string
+	// Offset is the position of the heading within the document
+	Offset int
+}
+
+// slugPunctuation matches everything GitLab removes from a heading to generate
+// its anchor, which is everything except word characters, spaces and hyphens
+var slugPunctuation = regexp.MustCompile(`[^\p{L}\p{M}\p{Nd}\p{Pc} -]`)
+
+// multipleHyphens matches consecutive hyphens, which are collapsed into one
+var multipleHyphens = regexp.MustCompile(`-{2,}`)
+
+// headingRegex matches ATX headings, eg. ## Heading
+var headingRegex = regexp.MustCompile(`^ {0,3}#{1,6}[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
+// Slugger generates the anchors of headings the same way GitLab does. As
+// duplicate headings get a numbered suffix, it has to see all headings of a
+// document in order.
+type Slugger struct {
+	seen map[string]int
+}
+
+func NewSlugger() *Slugger {
+	return &Slugger{seen: map[string]int{}}
+}
+
+// Slug returns the anchor of the heading, without the leading #
+func (s *Slugger) Slug(heading string) string {
+	slug := strings.ToLower(heading)
+	slug = slugPunctuation.ReplaceAllString(slug, "")
+	slug = strings.ReplaceAll(slug, " ", "-")
+	slug = multipleHyphens.ReplaceAllString(slug, "-")
+
+	count := s.seen[slug]
+	s.seen[slug] = count + 1
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", slug, count)
+	}
+	return slug
+}
+
+// fence tracks whether the lines of a markdown document are within a fenced
+// code block
+type fence struct {
+	marker string
+}
+
+// code reports whether the line is part of a fenced code block, including the
+// lines of the fences themselves. The lines have to be passed in order.
+func (f *fence) code(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if f.marker != "" {
+		if strings.HasPrefix(trimmed, f.marker) {
+			f.marker = ""
+		}
+		return true
+	}
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		f.marker = trimmed[:3]
+		return true
+	}
+	return false
+}
+
+// headings returns all headings of the markdown in order with their anchors
+func (s *Slugger) headings(markdown string) []Heading {
+	headings := []Heading{}
+	var f fence
+	offset := 0
+	for _, line := range strings.Split(markdown, "\n") {
+		start := offset
+		offset += len(line) + 1
+		// lines in code blocks are no headings, eg. comments in yaml
+		if f.code(line) {
+			continue
+		}
+		if m := headingRegex.FindStringSubmatch(line); m != nil {
+			headings = append(headings, Heading{Title: m[1], Anchor: s.Slug(m[1]), Offset: start})
+		}
+	}
+	return headings
+}
+
+// Headings returns all headings of the markdown document in order, with the
+// anchors GitLab generates for them
+func Headings(markdown string) []Heading {
+	return NewSlugger().headings(markdown)
+}
+
+// AnchorEntries returns a copy of the entries, where entries without link link
+// to the first heading at or after their offset. The offsets of the entries are
+// relative to base within the document of the headings. Entries without heading
+// link to the anchor of their title.
+func AnchorEntries(entries []TOCEntry, headings []Heading, base int) []TOCEntry {
+	resolved := make([]TOCEntry, len(entries))
+	for i, e := range entries {
+		if e.Link == "" {
+			e.Link = "#" + NewSlugger().Slug(e.Title)
+			for _, h := range headings {
+				if h.Offset >= base+e.Offset {
+					e.Link = "#" + h.Anchor
+					break
+				}
+			}
+		}
+		e.Children = AnchorEntries(e.Children, headings, base)
+		resolved[i] = e
+	}
+	return resolved
+}
+
+// Links returns the links of all entries without children by their title
+func Links(entries []TOCEntry) map[string]string {
+	links := map[string]string{}
+	for _, e := range entries {
+		if len(e.Children) == 0 {
+			links[e.Title] = e.Link
+		}
+		for title, link := range Links(e.Children) {
+			links[title] = link
+		}
+	}
+	return links
+}
+
+// TableOfContents renders the entries as nested markdown list. The links of the
+// entries have to be resolved already, eg. by AnchorEntries.
+func TableOfContents(entries []TOCEntry) string {
+	var sb strings.Builder
+	writeTOCEntries(&sb, entries, 0)
+	return sb.String()
+}
+
+func writeTOCEntries(sb *strings.Builder, entries []TOCEntry, depth int) {
+	for _, e := range entries {
+		fmt.Fprintf(sb, "%s- [%s](%s)\n", strings.Repeat("  ", depth), e.Title, e.Link)
+		writeTOCEntries(sb, e.Children, depth+1)
+	}
+}
@@ -0,0 +1,95 @@
+package gitlab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Slug(t *testing.T) {
+	tests := []struct {
+		heading  string
+		expected string
+	}{
+		{"This heading has spaces in it", "this-heading-has-spaces-in-it"},
+		{"This heading has a ä in it", "this-heading-has-a-ä-in-it"},
+		{"This heading has ünicöde in it 한글", "this-heading-has-ünicöde-in-it-한글"},
+		{"my-component", "my-component"},
+		{"Component: build (v1.0)", "component-build-v10"},
+		{"snake_case", "snake_case"},
+		{"Multiple --- hyphens", "multiple-hyphens"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.heading, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewSlugger().Slug(tt.heading))
+		})
+	}
+
+	t.Run("Duplicates", func(t *testing.T) {
+		s := NewSlugger()
+		assert.Equal(t, "build", s.Slug("build"))
+		assert.Equal(t, "build-1", s.Slug("Build"))
+		assert.Equal(t, "build-2", s.Slug("build"))
+		assert.Equal(t, "deploy", s.Slug("deploy"))
+	})
+}
+
+func Test_TableOfContents(t *testing.T) {
+	entries := []TOCEntry{
+		{Title: "Build", Link: "#build-1", Children: []TOCEntry{{Title: "build", Link: "#build-2"}, {Title: "docker", Link: "templates/docker/README.md"}}},
+		{Title: "Other components", Link: "#other-components", Children: []TOCEntry{{Title: "lint", Link: "#lint"}}},
+	}
+
+	expected := `- [Build](#build-1)
+  - [build](#build-2)
+  - [docker](templates/docker/README.md)
+- [Other components](#other-components)
+  - [lint](#lint)
+`
+	assert.Equal(t, expected, TableOfContents(entries))
+	assert.Equal(t, "", TableOfContents(nil))
+}
+
+func Test_Links(t *testing.T) {
+	entries := []TOCEntry{
+		{Title: "Build", Link: "#build", Children: []TOCEntry{{Title: "build", Link: "#build-1"}, {Title: "docker", Link: "templates/docker/README.md"}}},
+		{Title: "lint", Link: "#lint"},
+	}
+
+	assert.Equal(t, map[string]string{"build": "#build-1", "docker": "templates/docker/README.md", "lint": "#lint"}, Links(entries))
+}
+
+func Test_Headings(t *testing.T) {
+	markdown := "# Components\n\n## build\n\n```yaml\n# build\n```\n\n## build\n\n~~~\n# lint\n~~~\n"
+	assert.Equal(t, []Heading{
+		{Title: "Components", Anchor: "components", Offset: 0},
+		{Title: "build", Anchor: "build", Offset: 14},
+		{Title: "build", Anchor: "build-1", Offset: 45},
+	}, Headings(markdown))
+}
+
+func Test_AnchorEntries(t *testing.T) {
+	header := "# Components\n\n## Build\n\n"
+	all := "## Build\n\n### Component: build\n\n#### Usage\n\n### Component: docker\n\n## Other components\n\n### lint\n"
+	entries := []TOCEntry{
+		{Title: "Build", Offset: 0, Children: []TOCEntry{
+			{Title: "build", Offset: strings.Index(all, "### Component: build")},
+			{Title: "docker", Offset: strings.Index(all, "### Component: docker")},
+			{Title: "readme", Link: "templates/readme/README.md"},
+		}},
+		{Title: "Other components", Offset: strings.Index(all, "## Other"), Children: []TOCEntry{{Title: "lint", Offset: strings.Index(all, "### lint")}}},
+		{Title: "missing", Offset: len(all) + 1},
+	}
+
+	resolved := AnchorEntries(entries, Headings(header+all), len(header))
+	assert.Equal(t, "#build-1", resolved[0].Link)
+	assert.Equal(t, "#other-components", resolved[1].Link)
+	assert.Equal(t, map[string]string{
+		"build":   "#component-build",
+		"docker":  "#component-docker",
+		"readme":  "templates/readme/README.md",
+		"lint":    "#lint",
+		"missing": "#missing",
+	}, Links(resolved))
+}
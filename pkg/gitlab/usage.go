@@ -0,0 +1,75 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type UsageOptions struct {
+	// Project is the path of the component project, eg. my-group/my-components
+	Project string
+	// Version of the component, eg. 1.0.0 or ~latest
+	Version string
+	// Optional adds the optional inputs with their default
+	Optional bool
+}
+
+// Usage returns an include statement for the component, with a placeholder for each mandatory input
+func (c *Component) Usage(opts UsageOptions) string {
+	var sb strings.Builder
+
+	sb.WriteString("include:\n")
+	sb.WriteString(fmt.Sprintf("  - component: $CI_SERVER_FQDN/%s/%s@%s\n", strings.Trim(opts.Project, "/"), c.Name, opts.Version))
+
+	if c.Spec == nil || len(c.Spec.Inputs) == 0 {
+		return sb.String()
+	}
+
+	var inputs strings.Builder
+	for _, input := range c.Spec.SortedInputs() {
+		if input.Default != nil {
+			if opts.Optional {
+				inputs.WriteString(fmt.Sprintf("      %s: %s\n", input.Name, inlineYaml(*input.Default)))
+			}
+			continue
+		}
+
+		inputs.WriteString(fmt.Sprintf("      %s: <%s>", input.Name, input.Name))
+		if len(input.Options) > 0 {
+			inputs.WriteString(fmt.Sprintf(" # one of %s", strings.Join(input.OptionsStrings(), ", ")))
+		} else if input.Type != "" && input.Type != "string" {
+			inputs.WriteString(fmt.Sprintf(" # %s", input.Type))
+		}
+		inputs.WriteString("\n")
+	}
+
+	if inputs.Len() > 0 {
+		sb.WriteString("    inputs:\n")
+		sb.WriteString(inputs.String())
+	}
+
+	return sb.String()
+}
+
+// inlineYaml formats a value to be used as a value in a YAML mapping
+func inlineYaml(v Value) string {
+	if v.Kind != KindString {
+		// JSON is valid YAML for arrays and maps, scalars are kept as written
+		return v.String()
+	}
+	var node any = v.Value
+	if s, ok := v.Value.(string); ok && strings.Contains(s, "\n") {
+		// a block scalar would need to be indented, a quoted string fits in a line
+		node = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s, Style: yaml.DoubleQuotedStyle}
+	}
+	b, err := yaml.Marshal(node)
+	if err != nil {
+		return v.String()
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
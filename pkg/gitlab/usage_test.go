@@ -0,0 +1,79 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Usage(t *testing.T) {
+	component := &Component{Name: "deploy"}
+	yaml.Unmarshal([]byte(`
+spec:
+  inputs:
+    job-prefix:
+    environment:
+      options: ['test', 'staging', 'production']
+    concurrency:
+      type: number
+    stage:
+      default: deploy
+    empty:
+      default: ''
+    script:
+      default: |
+        echo "a"
+        echo b
+    paths:
+      type: array
+      default: [a, b]
+    dry-run:
+      type: boolean
+      default: false`), component)
+
+	t.Run("Mandatory", func(t *testing.T) {
+		expected := `include:
+  - component: $CI_SERVER_FQDN/group/project/deploy@1.0.0
+    inputs:
+      concurrency: <concurrency> # number
+      environment: <environment> # one of test, staging, production
+      job-prefix: <job-prefix>
+`
+		assert.Equal(t, expected, component.Usage(UsageOptions{Project: "/group/project/", Version: "1.0.0"}))
+	})
+
+	t.Run("Optional", func(t *testing.T) {
+		expected := `include:
+  - component: $CI_SERVER_FQDN/group/project/deploy@~latest
+    inputs:
+      concurrency: <concurrency> # number
+      dry-run: false
+      empty: ""
+      environment: <environment> # one of test, staging, production
+      job-prefix: <job-prefix>
+      paths: ["a","b"]
+      script: "echo \"a\"\necho b\n"
+      stage: deploy
+`
+		usage := component.Usage(UsageOptions{Project: "group/project", Version: "~latest", Optional: true})
+		assert.Equal(t, expected, usage)
+
+		// the snippet is valid YAML and keeps the default
+		var parsed struct {
+			Include []struct {
+				Inputs map[string]any `yaml:"inputs"`
+			} `yaml:"include"`
+		}
+		assert.NoError(t, yaml.Unmarshal([]byte(usage), &parsed))
+		assert.Equal(t, "echo \"a\"\necho b\n", parsed.Include[0].Inputs["script"])
+	})
+
+	t.Run("Without inputs", func(t *testing.T) {
+		expected := `include:
+  - component: $CI_SERVER_FQDN/group/project/minimal@~latest
+`
+		c := &Component{Name: "minimal"}
+		assert.Equal(t, expected, c.Usage(UsageOptions{Project: "group/project", Version: "~latest", Optional: true}))
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules reported when validating the usage of components
+const (
+	RuleUnknownComponent  = "unknown-component"
+	RuleMissingInput      = "missing-input"
+	RuleUnknownInput      = "unknown-input"
+	RuleInputNotInOptions = "input-not-in-options"
+	RuleInputNoRegexMatch = "input-regex-mismatch"
+	RuleInputWrongType    = "input-type-mismatch"
+)
+
+// ParseComponentReference splits a component reference like
+// $CI_SERVER_FQDN/my-group/my-project/my-component@1.0.0 into the path of the
+// project including the host, the name of the component and the version
+func ParseComponentReference(ref string) (project, name, version string) {
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		ref, version = ref[:i], ref[i+1:]
+	}
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		return ref[:i], ref[i+1:], version
+	}
+	return "", ref, version
+}
+
+// ValidateUsage validates the inputs passed to components included in a CI configuration.
+// Only includes of the project at projectPath (eg. my-group/my-components) are validated,
+// if projectPath is empty all includes with the name of a known component are validated.
+func ValidateUsage(file string, b []byte, components []*Component, projectPath string) []Finding {
+	l := &linter{file: file}
+
+	byName := map[string]*Component{}
+	for _, c := range components {
+		byName[c.Name] = c
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if err != io.EOF {
+				l.findings = append(l.findings, syntaxFinding(file, err))
+			}
+			break
+		}
+
+		for _, include := range includes(&doc) {
+			ref := lookup(include, "component")
+			if ref == nil {
+				continue
+			}
+
+			project, name, _ := ParseComponentReference(ref.Value)
+			projectPath = strings.Trim(projectPath, "/")
+			if projectPath != "" && !strings.HasSuffix(project, "/"+projectPath) {
+				continue
+			}
+
+			c, ok := byName[name]
+			if !ok {
+				if projectPath != "" {
+					l.report(ref, SeverityError, RuleUnknownComponent, "component %s does not exist", name)
+				}
+				continue
+			}
+
+			l.validateInputs(c, ref, lookup(include, "inputs"))
+		}
+	}
+
+	sortFindings(l.findings)
+	return l.findings
+}
+
+// includes returns all entries of the include keyword, which are mappings
+func includes(doc *yaml.Node) []*yaml.Node {
+	include := lookup(doc, "include")
+	if include == nil {
+		return nil
+	}
+	switch include.Kind {
+	case yaml.MappingNode:
+		return []*yaml.Node{include}
+	case yaml.SequenceNode:
+		entries := []*yaml.Node{}
+		for _, entry := range include.Content {
+			if entry.Kind == yaml.MappingNode {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	}
+	return nil
+}
+
+func (l *linter) validateInputs(c *Component, ref, inputs *yaml.Node) {
+	spec := c.Spec
+	if spec == nil {
+		spec = &ComponentSpec{}
+	}
+
+	for _, input := range spec.SortedInputs() {
+		if input.Default == nil && lookup(inputs, input.Name) == nil {
+			l.report(ref, SeverityError, RuleMissingInput, "mandatory input %s of component %s is missing", input.Name, c.Name)
+		}
+	}
+
+	if inputs == nil || inputs.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(inputs.Content); i += 2 {
+		key, value := inputs.Content[i], inputs.Content[i+1]
+		input, ok := spec.Inputs[key.Value]
+		if !ok {
+			l.report(key, SeverityError, RuleUnknownInput, "component %s has no input %s", c.Name, key.Value)
+			continue
+		}
+
+		inputType := input.Type
+		if inputType == "" {
+			inputType = "string"
+		}
+		if slices.Contains(inputTypes, inputType) && !matchesType(value, inputType) {
+			l.report(value, SeverityError, RuleInputWrongType, "input %s of component %s must be of type %s", key.Value, c.Name, inputType)
+		}
+
+		if len(input.Options) > 0 && value.Kind == yaml.ScalarNode && !slices.Contains(input.OptionsStrings(), value.Value) {
+			l.report(value, SeverityError, RuleInputNotInOptions, "input %s of component %s must be one of %s", key.Value, c.Name, strings.Join(input.OptionsStrings(), ", "))
+		}
+
+		if input.Regex != "" && value.Kind == yaml.ScalarNode {
+			if re, err := CompileRegex(input.Regex); err == nil && !re.MatchString(value.Value) {
+				l.report(value, SeverityError, RuleInputNoRegexMatch, "input %s of component %s does not match the regex %s", key.Value, c.Name, input.Regex)
+			}
+		}
+	}
+}
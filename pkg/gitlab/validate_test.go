@@ -0,0 +1,88 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_ParseComponentReference(t *testing.T) {
+	project, name, version := ParseComponentReference("$CI_SERVER_FQDN/group/project/deploy@1.0.0")
+	assert.Equal(t, "$CI_SERVER_FQDN/group/project", project)
+	assert.Equal(t, "deploy", name)
+	assert.Equal(t, "1.0.0", version)
+
+	project, name, version = ParseComponentReference("deploy")
+	assert.Equal(t, "", project)
+	assert.Equal(t, "deploy", name)
+	assert.Equal(t, "", version)
+}
+
+func Test_ValidateUsage(t *testing.T) {
+	component := &Component{Name: "deploy"}
+	yaml.Unmarshal([]byte(`
+spec:
+  inputs:
+    environment:
+      options: ['test', 'production']
+    version:
+      regex: /^v\d+$/
+    concurrency:
+      type: number
+      default: 1
+    stage:
+      default: deploy`), component)
+	components := []*Component{component}
+
+	t.Run("Valid", func(t *testing.T) {
+		input := `include:
+  - local: other.yml
+  - component: $CI_SERVER_FQDN/group/project/deploy@1.0.0
+    inputs:
+      environment: test
+      version: v1
+      concurrency: 2
+  - component: $CI_SERVER_FQDN/other/project/unknown@1.0.0`
+
+		assert.Empty(t, ValidateUsage(".gitlab-ci.yml", []byte(input), components, "group/project"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		input := `include:
+  - component: gitlab.com/group/project/deploy@~latest
+    inputs:
+      environment: staging
+      concurrency: "2"
+      unknown: value
+  - component: $CI_SERVER_FQDN/group/project/deploy@1.0.0
+    inputs:
+      environment: production
+      version: "1"
+  - component: $CI_SERVER_FQDN/group/project/missing@1.0.0
+  - component: $CI_SERVER_FQDN/other/project/deploy@1.0.0`
+
+		expected := []Finding{
+			{File: ".gitlab-ci.yml", Line: 2, Column: 16, Severity: SeverityError, Rule: RuleMissingInput, Message: "mandatory input version of component deploy is missing"},
+			{File: ".gitlab-ci.yml", Line: 4, Column: 20, Severity: SeverityError, Rule: RuleInputNotInOptions, Message: "input environment of component deploy must be one of test, production"},
+			{File: ".gitlab-ci.yml", Line: 5, Column: 20, Severity: SeverityError, Rule: RuleInputWrongType, Message: "input concurrency of component deploy must be of type number"},
+			{File: ".gitlab-ci.yml", Line: 6, Column: 7, Severity: SeverityError, Rule: RuleUnknownInput, Message: "component deploy has no input unknown"},
+			{File: ".gitlab-ci.yml", Line: 10, Column: 16, Severity: SeverityError, Rule: RuleInputNoRegexMatch, Message: `input version of component deploy does not match the regex /^v\d+$/`},
+			{File: ".gitlab-ci.yml", Line: 11, Column: 16, Severity: SeverityError, Rule: RuleUnknownComponent, Message: "component missing does not exist"},
+		}
+
+		assert.Equal(t, expected, ValidateUsage(".gitlab-ci.yml", []byte(input), components, "group/project"))
+	})
+
+	t.Run("Without project path", func(t *testing.T) {
+		input := `include:
+  component: $CI_SERVER_FQDN/other/project/deploy@1.0.0
+  inputs:
+    environment: test`
+
+		expected := []Finding{
+			{File: ".gitlab-ci.yml", Line: 2, Column: 14, Severity: SeverityError, Rule: RuleMissingInput, Message: "mandatory input version of component deploy is missing"},
+		}
+		assert.Equal(t, expected, ValidateUsage(".gitlab-ci.yml", []byte(input), components, ""))
+	})
+}
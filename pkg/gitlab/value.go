@@ -0,0 +1,103 @@
+/*
+Copyright © 2024 Mathias Petermann <mathias.petermann@gmail.com>
+*/
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ValueKind string
+
+const (
+	KindString  ValueKind = "string"
+	KindNumber  ValueKind = "number"
+	KindBoolean ValueKind = "boolean"
+	KindArray   ValueKind = "array"
+	KindMap     ValueKind = "map"
+)
+
+// Value holds a value of an input, eg. the default or an option, as it was
+// written in the YAML together with its kind. The zero value represents null.
+type Value struct {
+	Kind  ValueKind
+	Value any
+	// text is the value as written in the YAML, only set for scalars
+	text string
+}
+
+func (v *Value) UnmarshalYAML(node *yaml.Node) error {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+
+	v.Value = value
+	v.text = ""
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		v.Kind = KindArray
+	case yaml.MappingNode:
+		v.Kind = KindMap
+	default:
+		v.text = node.Value
+		switch value.(type) {
+		case nil:
+			v.Kind = ""
+		case bool:
+			v.Kind = KindBoolean
+		case int, int64, uint64, float64:
+			v.Kind = KindNumber
+		default:
+			v.Kind = KindString
+			// timestamps and binary values are kept as written
+			v.Value = node.Value
+		}
+	}
+
+	return nil
+}
+
+func (v Value) MarshalYAML() (any, error) {
+	return v.Value, nil
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Value)
+}
+
+// String returns scalars as written in the YAML, arrays and maps as JSON
+func (v Value) String() string {
+	switch v.Kind {
+	case KindArray, KindMap:
+		b, err := json.Marshal(v.Value)
+		if err != nil {
+			return fmt.Sprint(v.Value)
+		}
+		return string(b)
+	case "":
+		return "null"
+	}
+	if v.text == "" {
+		return fmt.Sprint(v.Value)
+	}
+	return v.text
+}
+
+func (v Value) Markdown() string {
+	switch v.Kind {
+	case KindString:
+		// an empty string is a valid default, which makes the input optional
+		if v.Value == "" {
+			return `""`
+		}
+		return fmt.Sprintf("_%s_", v.String())
+	}
+	// numbers, booleans, arrays and maps are rendered as code, to tell them
+	// apart from strings like "true"
+	return fmt.Sprintf("`%s`", v.String())
+}
@@ -0,0 +1,60 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		kind     ValueKind
+		value    any
+		str      string
+		markdown string
+	}{
+		{"String", `test`, KindString, "test", "test", "_test_"},
+		{"Empty string", `""`, KindString, "", "", `""`},
+		{"Quoted number", `"1"`, KindString, "1", "1", "_1_"},
+		{"Integer", `1`, KindNumber, 1, "1", "`1`"},
+		{"Float", `1.50`, KindNumber, 1.5, "1.50", "`1.50`"},
+		{"Boolean", `true`, KindBoolean, true, "true", "`true`"},
+		{"Quoted boolean", `"true"`, KindString, "true", "true", "_true_"},
+		{"Array", `['a', 'b']`, KindArray, []any{"a", "b"}, `["a","b"]`, "`[\"a\",\"b\"]`"},
+		{"Empty array", `[]`, KindArray, []any{}, `[]`, "`[]`"},
+		{"Map", `{key: value}`, KindMap, map[string]any{"key": "value"}, `{"key":"value"}`, "`{\"key\":\"value\"}`"},
+		{"Null", `~`, "", nil, "null", "`null`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Value
+			assert.NoError(t, yaml.Unmarshal([]byte(tt.input), &v))
+
+			assert.Equal(t, tt.kind, v.Kind)
+			assert.Equal(t, tt.value, v.Value)
+			assert.Equal(t, tt.str, v.String())
+			assert.Equal(t, tt.markdown, v.Markdown())
+		})
+	}
+}
+
+func Test_InputDefault(t *testing.T) {
+	spec := &ComponentSpec{}
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+inputs:
+  missing:
+    description: no default
+  empty:
+    default: ''
+  null:
+    default:
+`), spec))
+
+	assert.Nil(t, spec.Inputs["missing"].Default)
+	assert.Equal(t, &Value{Kind: KindString, Value: "", text: ""}, spec.Inputs["empty"].Default)
+	assert.Nil(t, spec.Inputs["null"].Default)
+}